@@ -0,0 +1,221 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/go-redis/redis"
+	"golang.org/x/sync/singleflight"
+
+	ctxPkg "github.com/tmeisel/glib/ctx"
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+var (
+	ErrCacheMiss = errPkg.New(errPkg.CodeNotFound, "cache miss", nil)
+)
+
+// Cache stores JSON encoded values. Use the functions Get and
+// GetOrLoad to read typed values from the cache
+type Cache struct {
+	r     *Redis
+	name  string
+	beta  float64
+	group *singleflight.Group
+}
+
+// cacheEntry is the representation of a value in redis. Delta and
+// Expires are used to refresh entries before they expire
+type cacheEntry struct {
+	Value   json.RawMessage `json:"v"`
+	Delta   int64           `json:"d,omitempty"`
+	Expires int64           `json:"e,omitempty"`
+}
+
+// LoadFn loads a value, that is missing in the cache
+type LoadFn[T any] func(ctx context.Context) (T, error)
+
+// Cache initializes a cache with the given name. Keys of
+// different caches do not collide
+func (r Redis) Cache(name string) Cache {
+	return Cache{
+		r:     &r,
+		name:  fmt.Sprintf("cache-%v", name),
+		group: &singleflight.Group{},
+	}
+}
+
+// WithEarlyRefresh returns a copy of the cache, that lets GetOrLoad reload
+// entries before they expire, with a probability growing towards their
+// expiration (see "Optimal Probabilistic Cache Stampede Prevention"). Entries
+// that took longer to load are refreshed earlier. A beta of 1 is a sensible
+// default, larger values refresh earlier. A beta of 0 disables early refresh
+func (c Cache) WithEarlyRefresh(beta float64) Cache {
+	c.beta = beta
+
+	return c
+}
+
+func (c Cache) Name() string {
+	return c.name
+}
+
+// Set stores the JSON encoding of value. If ttl is 0,
+// the entry does not expire
+func (c Cache) Set(key string, value interface{}, ttl time.Duration) error {
+	return c.set(key, value, ttl, 0)
+}
+
+// Delete removes the given keys from the cache
+func (c Cache) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	redisKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		redisKeys = append(redisKeys, c.key(key))
+	}
+
+	if err := c.r.client.Del(redisKeys...).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to delete cache entries")
+	}
+
+	return nil
+}
+
+// Get returns the value stored for key. If there is none, ErrCacheMiss is returned
+func Get[T any](c Cache, key string) (T, error) {
+	var value T
+
+	entry, err := c.get(key)
+	if err != nil {
+		return value, err
+	}
+
+	return value, entry.decode(&value)
+}
+
+// GetOrLoad returns the value stored for key. On a cache miss, the value is
+// loaded using load and stored with the given ttl. Concurrent calls for the
+// same key within the process share a single call of load. load is called
+// with a context, that is not cancelled if ctx is done, so other callers
+// waiting for the same key are not affected. Calls with different types
+// do not share a call of load, as the loaded value could not be returned
+func GetOrLoad[T any](ctx context.Context, c Cache, key string, ttl time.Duration, load LoadFn[T]) (T, error) {
+	var value T
+
+	entry, err := c.get(key)
+	if err != nil && !errors.Is(err, ErrCacheMiss) {
+		return value, err
+	}
+
+	if err == nil && !entry.refresh(c.beta) {
+		return value, entry.decode(&value)
+	}
+
+	flight := fmt.Sprintf("%s\x00%T", c.key(key), new(T))
+
+	result := c.group.DoChan(flight, func() (interface{}, error) {
+		started := time.Now()
+
+		loaded, err := load(ctxPkg.Disconnect(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.set(key, loaded, ttl, time.Since(started)); err != nil {
+			return nil, err
+		}
+
+		return loaded, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return value, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return value, res.Err
+		}
+
+		loaded, ok := res.Val.(T)
+		if !ok {
+			return value, errPkg.NewInternalMsg(nil, "loaded value has an unexpected type")
+		}
+
+		return loaded, nil
+	}
+}
+
+func (c Cache) get(key string) (cacheEntry, error) {
+	var entry cacheEntry
+
+	raw, err := c.r.client.Get(c.key(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entry, ErrCacheMiss
+		}
+
+		return entry, errPkg.NewInternalMsg(err, "failed to get cache entry")
+	}
+
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return entry, errPkg.NewInternalMsg(err, "failed to decode cache entry")
+	}
+
+	return entry, nil
+}
+
+func (c Cache) set(key string, value interface{}, ttl, delta time.Duration) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to encode value")
+	}
+
+	entry := cacheEntry{Value: raw}
+	if ttl > 0 {
+		entry.Delta = delta.Milliseconds()
+		entry.Expires = time.Now().Add(ttl).UnixMilli()
+	}
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to encode cache entry")
+	}
+
+	if err := c.r.client.Set(c.key(key), encoded, ttl).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to set cache entry")
+	}
+
+	return nil
+}
+
+func (c Cache) key(key string) string {
+	return fmt.Sprintf("%s-%s", c.name, key)
+}
+
+func (e cacheEntry) decode(value interface{}) error {
+	if err := json.Unmarshal(e.Value, value); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to decode value")
+	}
+
+	return nil
+}
+
+// refresh decides whether the entry should be reloaded before it expires:
+// now - delta * beta * ln(rand()) >= expires
+func (e cacheEntry) refresh(beta float64) bool {
+	if beta <= 0 || e.Expires == 0 {
+		return false
+	}
+
+	gap := -float64(e.Delta) * beta * math.Log(rand.Float64())
+
+	return float64(time.Now().UnixMilli())+gap >= float64(e.Expires)
+}
@@ -0,0 +1,184 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+// Config holds the connection settings. Durations and pool sizes of zero
+// fall back to the defaults of github.com/go-redis/redis
+type Config struct {
+	Addresses []string `envconfig:"ADDRESS" default:"localhost:6379"`
+	Database  int      `envconfig:"DATABASE" default:"0"`
+
+	// Username is used to authenticate against an ACL user (redis 6+).
+	// If it is empty, Password authenticates the default user. go-redis
+	// v6 does not support ACL users, so they authenticate with Username
+	// and Password after connecting to a master or cluster node. Sentinel
+	// connections are never authenticated by go-redis v6
+	Username string `envconfig:"USERNAME"`
+	Password string `envconfig:"PASSWORD"`
+
+	// MasterName is the name of the master monitored by Sentinel. If it
+	// is set, Addresses are the addresses of the sentinels
+	MasterName string `envconfig:"SENTINEL_MASTER"`
+
+	// Cluster forces a cluster client, even if only a single address is
+	// given. Multiple addresses without MasterName always use a cluster
+	// client. Database is not supported in cluster mode. See Redis.Queue
+	// on how the keys of queues are stored in cluster mode
+	Cluster bool `envconfig:"CLUSTER" default:"false"`
+
+	PoolSize     int           `envconfig:"POOL_SIZE"`
+	MinIdleConns int           `envconfig:"MIN_IDLE_CONNS"`
+	DialTimeout  time.Duration `envconfig:"DIAL_TIMEOUT"`
+	ReadTimeout  time.Duration `envconfig:"READ_TIMEOUT"`
+	WriteTimeout time.Duration `envconfig:"WRITE_TIMEOUT"`
+	PoolTimeout  time.Duration `envconfig:"POOL_TIMEOUT"`
+	IdleTimeout  time.Duration `envconfig:"IDLE_TIMEOUT"`
+
+	TLS TLSConfig `envconfig:"TLS"`
+}
+
+type TLSConfig struct {
+	Enabled bool `envconfig:"ENABLED" default:"false"`
+
+	// CAFile is a PEM encoded bundle of the certificate authorities
+	// to verify the server with. If empty, the system pool is used
+	CAFile string `envconfig:"CA_FILE"`
+
+	// CertFile and KeyFile hold the PEM encoded client certificate
+	// and its key. Both must be set to use client authentication
+	CertFile string `envconfig:"CERT_FILE"`
+	KeyFile  string `envconfig:"KEY_FILE"`
+
+	ServerName         string `envconfig:"SERVER_NAME"`
+	InsecureSkipVerify bool   `envconfig:"INSECURE_SKIP_VERIFY" default:"false"`
+}
+
+// Build returns the *tls.Config described by c or nil,
+// if TLS is not enabled
+func (c TLSConfig) Build() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+
+	conf := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, errPkg.NewInternalMsg(err, "failed to read ca file")
+		}
+
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errPkg.NewInternalMsg(nil, "ca file does not contain any certificate")
+		}
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, errPkg.NewInternalMsg(err, "failed to load client certificate")
+		}
+
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return conf, nil
+}
+
+func (c Config) options() (*redis.UniversalOptions, error) {
+	tlsConfig, err := c.TLS.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:        c.Addresses,
+		DB:           c.Database,
+		Password:     c.Password,
+		MasterName:   c.MasterName,
+		PoolSize:     c.PoolSize,
+		MinIdleConns: c.MinIdleConns,
+		DialTimeout:  c.DialTimeout,
+		ReadTimeout:  c.ReadTimeout,
+		WriteTimeout: c.WriteTimeout,
+		PoolTimeout:  c.PoolTimeout,
+		IdleTimeout:  c.IdleTimeout,
+		TLSConfig:    tlsConfig,
+	}
+
+	if c.Username != "" {
+		// go-redis v6 sends AUTH <password>, which authenticates the
+		// default user, and selects the database before OnConnect is
+		// called. Both are done in OnConnect for the ACL user instead
+		opts.Password = ""
+		opts.DB = 0
+		opts.OnConnect = c.authenticate
+	}
+
+	return opts, nil
+}
+
+// authenticate authenticates an ACL user on a new connection
+func (c Config) authenticate(conn *redis.Conn) error {
+	if err := conn.Process(redis.NewStatusCmd("auth", c.Username, c.Password)); err != nil {
+		return err
+	}
+
+	if c.Database > 0 && !c.Cluster {
+		return conn.Select(c.Database).Err()
+	}
+
+	return nil
+}
+
+// clusterOptions returns the cluster options of opts. It equals the unexported
+// UniversalOptions.cluster of go-redis, which NewUniversalClient only uses for
+// multiple addresses, so a single address can be used in cluster mode as well
+func clusterOptions(opts *redis.UniversalOptions) *redis.ClusterOptions {
+	addrs := opts.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{"127.0.0.1:6379"}
+	}
+
+	return &redis.ClusterOptions{
+		Addrs:     addrs,
+		OnConnect: opts.OnConnect,
+
+		Password: opts.Password,
+
+		MaxRedirects:   opts.MaxRedirects,
+		ReadOnly:       opts.ReadOnly,
+		RouteByLatency: opts.RouteByLatency,
+		RouteRandomly:  opts.RouteRandomly,
+
+		MaxRetries:      opts.MaxRetries,
+		MinRetryBackoff: opts.MinRetryBackoff,
+		MaxRetryBackoff: opts.MaxRetryBackoff,
+
+		DialTimeout:        opts.DialTimeout,
+		ReadTimeout:        opts.ReadTimeout,
+		WriteTimeout:       opts.WriteTimeout,
+		PoolSize:           opts.PoolSize,
+		MinIdleConns:       opts.MinIdleConns,
+		MaxConnAge:         opts.MaxConnAge,
+		PoolTimeout:        opts.PoolTimeout,
+		IdleTimeout:        opts.IdleTimeout,
+		IdleCheckFrequency: opts.IdleCheckFrequency,
+
+		TLSConfig: opts.TLSConfig,
+	}
+}
@@ -0,0 +1,191 @@
+package redis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfig_Build(t *testing.T) {
+	dir := t.TempDir()
+
+	invalid := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("no certificate"), 0o600))
+
+	type testCase struct {
+		config  TLSConfig
+		expNil  bool
+		expErr  bool
+		expSkip bool
+	}
+
+	for name, tc := range map[string]testCase{
+		"disabled": {
+			config: TLSConfig{CAFile: invalid},
+			expNil: true,
+		},
+		"enabled": {
+			config: TLSConfig{Enabled: true},
+		},
+		"insecure skip verify": {
+			config:  TLSConfig{Enabled: true, InsecureSkipVerify: true},
+			expSkip: true,
+		},
+		"missing ca file": {
+			config: TLSConfig{Enabled: true, CAFile: filepath.Join(dir, "missing.pem")},
+			expErr: true,
+		},
+		"invalid ca file": {
+			config: TLSConfig{Enabled: true, CAFile: invalid},
+			expErr: true,
+		},
+		"invalid client certificate": {
+			config: TLSConfig{Enabled: true, CertFile: invalid, KeyFile: invalid},
+			expErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf, err := tc.config.Build()
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.expNil {
+				assert.Nil(t, conf)
+				return
+			}
+
+			require.NotNil(t, conf)
+			assert.Equal(t, tc.expSkip, conf.InsecureSkipVerify)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	r := New(Config{Addresses: []string{"localhost:6379"}})
+	assert.NotNil(t, r.client)
+	assert.IsType(t, &redis.Client{}, r.client)
+
+	invalid := New(Config{TLS: TLSConfig{Enabled: true, CAFile: filepath.Join(t.TempDir(), "missing.pem")}})
+	err := invalid.Ping()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(Config{TLS: TLSConfig{Enabled: true, CAFile: filepath.Join(t.TempDir(), "missing.pem")}})
+	require.Error(t, err)
+
+	type testCase struct {
+		config     Config
+		expCluster bool
+	}
+
+	for name, tc := range map[string]testCase{
+		"single": {
+			config: Config{Addresses: []string{"localhost:6379"}},
+		},
+		"forced cluster": {
+			config:     Config{Addresses: []string{"localhost:6379"}, Cluster: true},
+			expCluster: true,
+		},
+		"multiple addresses": {
+			config:     Config{Addresses: []string{"localhost:7000", "localhost:7001"}},
+			expCluster: true,
+		},
+		"sentinel": {
+			config: Config{Addresses: []string{"localhost:26379", "localhost:26380"}, MasterName: "master"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewClient(tc.config)
+			require.NoError(t, err)
+			require.NotNil(t, r.client)
+
+			_, isCluster := r.client.(*redis.ClusterClient)
+			assert.Equal(t, tc.expCluster, isCluster)
+		})
+	}
+}
+
+func TestConfig_options(t *testing.T) {
+	type testCase struct {
+		config       Config
+		expPassword  string
+		expDB        int
+		expOnConnect bool
+	}
+
+	for name, tc := range map[string]testCase{
+		"default user": {
+			config:      Config{Database: 2, Password: "secret"},
+			expPassword: "secret",
+			expDB:       2,
+		},
+		"acl user": {
+			config:       Config{Database: 2, Username: "user", Password: "secret"},
+			expOnConnect: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts, err := tc.config.options()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expPassword, opts.Password)
+			assert.Equal(t, tc.expDB, opts.DB)
+			assert.Equal(t, tc.expOnConnect, opts.OnConnect != nil)
+			assert.Equal(t, tc.expPassword, clusterOptions(opts).Password)
+		})
+	}
+}
+
+func TestClusterOptions(t *testing.T) {
+	assert.Equal(t, []string{"127.0.0.1:6379"}, clusterOptions(&redis.UniversalOptions{}).Addrs)
+
+	opts := &redis.UniversalOptions{
+		Addrs:              []string{"localhost:7000"},
+		Password:           "secret",
+		MaxRedirects:       5,
+		ReadOnly:           true,
+		MaxRetries:         3,
+		MinRetryBackoff:    time.Millisecond,
+		MaxRetryBackoff:    time.Second,
+		MaxConnAge:         time.Minute,
+		IdleCheckFrequency: time.Hour,
+	}
+
+	conf := clusterOptions(opts)
+	assert.Equal(t, opts.Addrs, conf.Addrs)
+	assert.Equal(t, opts.Password, conf.Password)
+	assert.Equal(t, opts.MaxRedirects, conf.MaxRedirects)
+	assert.Equal(t, opts.ReadOnly, conf.ReadOnly)
+	assert.Equal(t, opts.MaxRetries, conf.MaxRetries)
+	assert.Equal(t, opts.MinRetryBackoff, conf.MinRetryBackoff)
+	assert.Equal(t, opts.MaxRetryBackoff, conf.MaxRetryBackoff)
+	assert.Equal(t, opts.MaxConnAge, conf.MaxConnAge)
+	assert.Equal(t, opts.IdleCheckFrequency, conf.IdleCheckFrequency)
+}
+
+func TestDerivedKey(t *testing.T) {
+	type testCase struct {
+		key string
+		exp string
+	}
+
+	for name, tc := range map[string]testCase{
+		"plain key": {key: "queue-orders", exp: "{queue-orders}-delayed"},
+		"hash tag":  {key: "queue-{orders}-failed", exp: "queue-{orders}-failed-delayed"},
+		"empty tag": {key: "queue-{}orders", exp: "{queue-{}orders}-delayed"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.exp, derivedKey(tc.key, "delayed"))
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis"
+
+	errPkg "github.com/tmeisel/glib/error"
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+// deadLetterScript removes a single value (ARGV[1]) from the processing
+// list (KEYS[1]) and pushes the encoded dead letter (ARGV[2]) to the
+// dead-letter list (KEYS[2])
+var deadLetterScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+	return 0
+end
+redis.call('LPUSH', KEYS[2], ARGV[2])
+return 1
+`)
+
+// replayScript pops up to ARGV[1] of the oldest dead letters from
+// the dead-letter list (KEYS[1]) and pushes their values back to
+// the queue (KEYS[2])
+var replayScript = redis.NewScript(`
+local n = 0
+while n < tonumber(ARGV[1]) do
+	local raw = redis.call('RPOP', KEYS[1])
+	if not raw then
+		break
+	end
+	redis.call('LPUSH', KEYS[2], cjson.decode(raw).value)
+	n = n + 1
+end
+return n
+`)
+
+var _ queuePkg.DeadLetterQueue = ReliableQueue{}
+
+// WithDeadLetter returns a copy of the queue, that moves messages to the
+// dead-letter queue with the given name, once they have been delivered more
+// than maxDeliveries times. If destination is empty, the default dead-letter
+// queue of the queue is used. A maxDeliveries of 0 disables the limit.
+//
+// In cluster mode, the key of destination must be stored in the slot of the
+// queue, e.g. "{queue-orders}-failed" for the queue "orders". See Redis.Queue
+func (q ReliableQueue) WithDeadLetter(destination string, maxDeliveries int) ReliableQueue {
+	if destination != "" {
+		q.deadLetter = fmt.Sprintf("queue-%v", destination)
+	}
+
+	q.maxDeliveries = maxDeliveries
+
+	return q
+}
+
+// DeadLetterName returns the name of the dead-letter queue
+func (q ReliableQueue) DeadLetterName() string {
+	return q.deadLetter
+}
+
+// DeadLetters returns up to limit dead-lettered elements, starting at offset.
+// The most recent element comes first
+func (q ReliableQueue) DeadLetters(offset, limit int) ([]queuePkg.DeadLetter, error) {
+	if limit <= 0 {
+		return make([]queuePkg.DeadLetter, 0), nil
+	}
+
+	raw, err := q.r.client.LRange(q.deadLetter, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, errPkg.NewInternalMsg(err, "failed to list dead letters")
+	}
+
+	output := make([]queuePkg.DeadLetter, 0, len(raw))
+	for _, entry := range raw {
+		var dl queuePkg.DeadLetter
+		if err := json.Unmarshal([]byte(entry), &dl); err != nil {
+			return nil, errPkg.NewInternalMsg(err, "failed to decode dead letter")
+		}
+
+		output = append(output, dl)
+	}
+
+	return output, nil
+}
+
+// Replay pushes up to n of the oldest dead-lettered elements back to
+// the queue. Their number of deliveries starts at 0 again
+func (q ReliableQueue) Replay(n int) (int, error) {
+	if n <= 0 {
+		return 0, nil
+	}
+
+	replayed, err := replayScript.Run(q.r.client, []string{q.deadLetter, q.name}, n).Int()
+	if err != nil {
+		return 0, errPkg.NewInternalMsg(err, "failed to replay dead letters")
+	}
+
+	return replayed, nil
+}
+
+// Purge removes all dead-lettered elements
+func (q ReliableQueue) Purge() error {
+	if err := q.r.client.Del(q.deadLetter).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to purge dead letters")
+	}
+
+	return nil
+}
+
+// deadLetterValue moves the element raw of the processing list
+// to the dead-letter queue. value is the unwrapped element
+func (q ReliableQueue) deadLetterValue(raw, value string, reason error, deliveries int) error {
+	encoded, err := json.Marshal(queuePkg.NewDeadLetter(value, reason, deliveries))
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to encode dead letter")
+	}
+
+	keys := []string{q.processingKey(), q.deadLetter}
+
+	n, err := deadLetterScript.Run(q.r.client, keys, raw, string(encoded)).Int()
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to dead-letter value")
+	}
+
+	if n == 0 {
+		return ErrNotInFlight
+	}
+
+	return nil
+}
@@ -1,17 +1,17 @@
 module github.com/tmeisel/glib/clients/redis
 
-go 1.22
-
-toolchain go1.23.1
+go 1.23.1
 
 require (
 	github.com/go-redis/redis v6.15.9+incompatible
 	github.com/google/uuid v1.6.0
 	github.com/stretchr/testify v1.9.0
-	github.com/tmeisel/glib/error v0.0.6
+	github.com/tmeisel/glib/ctx v0.0.7
+	github.com/tmeisel/glib/error v0.0.10
 	github.com/tmeisel/glib/exec v0.0.1
 	github.com/tmeisel/glib/queue v0.0.4
 	github.com/tmeisel/glib/testing v0.0.2
+	golang.org/x/sync v0.8.0
 	gotest.tools v2.2.0+incompatible
 )
 
@@ -35,7 +35,10 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/sethvargo/go-retry v0.3.0 // indirect
 	github.com/sirupsen/logrus v1.9.3 // indirect
-	github.com/tmeisel/glib/utils v0.0.1 // indirect
+	github.com/tmeisel/glib/log v0.0.3 // indirect
+	github.com/tmeisel/glib/utils v0.0.2 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.27.0 // indirect
 	golang.org/x/net v0.30.0 // indirect
 	golang.org/x/sys v0.26.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
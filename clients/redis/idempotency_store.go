@@ -0,0 +1,143 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+var (
+	ErrNotReserved = errPkg.New(errPkg.CodeGone, "idempotency key is no longer reserved", nil)
+)
+
+// reserveScript stores the fingerprint ARGV[1] in the hash KEYS[1] and
+// lets it expire after ARGV[2] milliseconds, if the hash doesn't exist.
+// Otherwise, it returns the stored fingerprint and response
+var reserveScript = redis.NewScript(`
+if redis.call('HSETNX', KEYS[1], 'fingerprint', ARGV[1]) == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return {}
+end
+return redis.call('HMGET', KEYS[1], 'fingerprint', 'response')
+`)
+
+// completeScript stores the response ARGV[2] in the hash KEYS[1] and lets
+// it expire after ARGV[3] milliseconds, if it holds the fingerprint ARGV[1]
+var completeScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'fingerprint') ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'response', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// releaseReservationScript deletes the hash KEYS[1], if it holds the
+// fingerprint ARGV[1], but no response
+var releaseReservationScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'fingerprint') ~= ARGV[1] or redis.call('HEXISTS', KEYS[1], 'response') == 1 then
+	return 0
+end
+return redis.call('DEL', KEYS[1])
+`)
+
+// IdempotencyStore stores responses by their idempotency key. It
+// implements idempotency.Store of the package
+// github.com/tmeisel/glib/net/http/middleware/idempotency
+type IdempotencyStore struct {
+	r    *Redis
+	name string
+}
+
+// IdempotencyStore initializes a store with the given name. Keys
+// of different stores do not collide
+func (r Redis) IdempotencyStore(name string) IdempotencyStore {
+	return IdempotencyStore{
+		r:    &r,
+		name: fmt.Sprintf("idempotency-%v", name),
+	}
+}
+
+func (s IdempotencyStore) Name() string {
+	return s.name
+}
+
+// Reserve stores fingerprint for key for the duration of ttl, if key is
+// unknown. Otherwise, it returns the fingerprint and the response stored
+// previously. An empty fingerprint means, that the key has been reserved
+// by this call. A nil response means, that the request is in progress
+func (s IdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (string, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return "", nil, err
+	}
+
+	res, err := reserveScript.Run(s.r.client, []string{s.key(key)}, fingerprint, ttl.Milliseconds()).Result()
+	if err != nil {
+		return "", nil, errPkg.NewInternalMsg(err, "failed to reserve idempotency key")
+	}
+
+	values, ok := res.([]interface{})
+	if !ok {
+		return "", nil, errPkg.NewInternalMsg(nil, "unexpected response from redis")
+	}
+
+	if len(values) == 0 {
+		return "", nil, nil
+	}
+
+	if len(values) != 2 {
+		return "", nil, errPkg.NewInternalMsg(nil, "unexpected response from redis")
+	}
+
+	storedFingerprint, _ := values[0].(string)
+
+	// the response is nil, while the request is in progress
+	var response []byte
+	if value, ok := values[1].(string); ok {
+		response = []byte(value)
+	}
+
+	return storedFingerprint, response, nil
+}
+
+// Complete stores the response for a key reserved with fingerprint and keeps
+// it for the duration of ttl. If the reservation expired in the meantime,
+// ErrNotReserved is returned
+func (s IdempotencyStore) Complete(ctx context.Context, key, fingerprint string, response []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	n, err := completeScript.Run(s.r.client, []string{s.key(key)}, fingerprint, response, ttl.Milliseconds()).Int()
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to store response")
+	}
+
+	if n == 0 {
+		return ErrNotReserved
+	}
+
+	return nil
+}
+
+// Release removes the reservation of key, if it is still reserved
+// with fingerprint and no response has been stored
+func (s IdempotencyStore) Release(ctx context.Context, key, fingerprint string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := releaseReservationScript.Run(s.r.client, []string{s.key(key)}, fingerprint).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to release idempotency key")
+	}
+
+	return nil
+}
+
+func (s IdempotencyStore) key(key string) string {
+	return fmt.Sprintf("%s-%s", s.name, key)
+}
@@ -0,0 +1,239 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/google/uuid"
+
+	errPkg "github.com/tmeisel/glib/error"
+	"github.com/tmeisel/glib/exec/backoff"
+)
+
+// DefaultLockRetryInterval is the interval, in which Mutex.Lock
+// retries to acquire a lock, if no backoff has been configured
+const DefaultLockRetryInterval = time.Millisecond * 100
+
+var (
+	ErrLockHeld    = errPkg.New(errPkg.CodeConflict, "lock is held by someone else", nil)
+	ErrLockNotHeld = errPkg.New(errPkg.CodeGone, "lock is no longer held", nil)
+)
+
+// releaseScript deletes the lock (KEYS[1]), if it still holds the token ARGV[1]
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// extendScript resets the ttl of the lock (KEYS[1]) to ARGV[2] milliseconds,
+// if it still holds the token ARGV[1]
+var extendScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// Mutex is a distributed lock. A Mutex can be copied and shared freely,
+// each successful call to TryLock or Lock returns a distinct *Lock
+type Mutex struct {
+	r         *Redis
+	name      string
+	ttl       time.Duration
+	backoffFn func() *backoff.Backoff
+}
+
+// Mutex initializes a distributed lock with the given name. A lock expires, if
+// it is not extended within ttl, e.g. because its holder crashed. ttl must be
+// at least 100 milliseconds. If the given value is smaller, it will be
+// overwritten with 100 milliseconds
+func (r Redis) Mutex(name string, ttl time.Duration) Mutex {
+	if ttl < time.Millisecond*100 {
+		ttl = time.Millisecond * 100
+	}
+
+	return Mutex{
+		r:         &r,
+		name:      fmt.Sprintf("lock-%v", name),
+		ttl:       ttl,
+		backoffFn: defaultLockBackoff,
+	}
+}
+
+// WithBackoff returns a copy of the mutex, that retries acquiring the
+// lock in Mutex.Lock according to the backoff returned by fn. fn is
+// called for every call of Lock, as limits like backoff.WithMaxRetries
+// are not reset between calls
+func (m Mutex) WithBackoff(fn func() *backoff.Backoff) Mutex {
+	m.backoffFn = fn
+
+	return m
+}
+
+func (m Mutex) Name() string {
+	return m.name
+}
+
+// TryLock tries to acquire the lock once. If it is held by someone
+// else, ErrLockHeld is returned. The lock is extended automatically
+// until Lock.Unlock is called or ctx is done
+func (m Mutex) TryLock(ctx context.Context) (*Lock, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	token := uuid.NewString()
+
+	ok, err := m.r.client.SetNX(m.name, token, m.ttl).Result()
+	if err != nil {
+		return nil, errPkg.NewInternalMsg(err, "failed to acquire lock")
+	}
+
+	if !ok {
+		return nil, ErrLockHeld
+	}
+
+	return m.newLock(ctx, token), nil
+}
+
+// Lock blocks until the lock is acquired, ctx is done or the backoff
+// gives up. In the latter case, ErrLockHeld is returned. The lock is
+// extended automatically until Lock.Unlock is called or ctx is done
+func (m Mutex) Lock(ctx context.Context) (*Lock, error) {
+	var lock *Lock
+
+	err := m.backoffFn().Do(ctx, func(ctx context.Context) error {
+		var err error
+
+		lock, err = m.TryLock(ctx)
+		if errors.Is(err, ErrLockHeld) {
+			return backoff.RetryableError(err)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+func defaultLockBackoff() *backoff.Backoff {
+	return backoff.NewConstant(DefaultLockRetryInterval)
+}
+
+func (m Mutex) newLock(ctx context.Context, token string) *Lock {
+	ctx, cancel := context.WithCancel(ctx)
+
+	l := &Lock{
+		m:      m,
+		token:  token,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go l.keepAlive(ctx)
+
+	return l
+}
+
+// Lock is a lock held on a Mutex
+type Lock struct {
+	m     Mutex
+	token string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Done returns a channel, that is closed when the lock is released,
+// or when it could not be extended in time and may be held by
+// someone else. Work that must not run concurrently should stop
+func (l *Lock) Done() <-chan struct{} {
+	return l.done
+}
+
+// Extend resets the ttl of the lock. It returns ErrLockNotHeld,
+// if the lock expired and may have been acquired by someone else
+func (l *Lock) Extend() error {
+	n, err := extendScript.Run(
+		l.m.r.client,
+		[]string{l.m.name},
+		l.token,
+		int64(l.m.ttl/time.Millisecond),
+	).Int()
+
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to extend lock")
+	}
+
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// Unlock stops the extension and releases the lock. It returns
+// ErrLockNotHeld, if the lock expired in the meantime
+func (l *Lock) Unlock() error {
+	l.cancel()
+	l.close()
+
+	n, err := releaseScript.Run(l.m.r.client, []string{l.m.name}, l.token).Int()
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to release lock")
+	}
+
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// keepAlive extends the lock three times per ttl, so a single failed
+// attempt does not lose the lock. It closes Done, once the lock is
+// released, ctx is done or the lock has been lost
+func (l *Lock) keepAlive(ctx context.Context) {
+	defer l.close()
+
+	interval := l.m.ttl / 3
+	expires := time.Now().Add(l.m.ttl)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := l.Extend()
+			if err == nil {
+				expires = time.Now().Add(l.m.ttl)
+				continue
+			}
+
+			if errors.Is(err, ErrLockNotHeld) || time.Now().Add(interval).After(expires) {
+				logError(ctx, "lost lock %s: %v", l.m.name, err)
+				return
+			}
+		}
+	}
+}
+
+func (l *Lock) close() {
+	l.once.Do(func() {
+		close(l.done)
+	})
+}
@@ -0,0 +1,143 @@
+package redis
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	errPkg "github.com/tmeisel/glib/error"
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+// PriorityQueue is a queue that holds a list per priority. Pop
+// returns an element of the highest priority available
+type PriorityQueue struct {
+	r           *Redis
+	name        string
+	levels      int
+	readTimeout time.Duration
+	fairness    uint64
+	pops        *atomic.Uint64
+}
+
+var _ queuePkg.PriorityQueue = PriorityQueue{}
+
+// PriorityQueue initializes the client to connect to the priority queue with
+// the given name. Priorities range from 0 (lowest) to levels-1 (highest).
+// levels must be at least 1. readTimeout must be at least 1 second. If the
+// given values are smaller, they will be overwritten with 1 and time.Second.
+// The keys of all priorities are stored in the same slot of a cluster
+func (r Redis) PriorityQueue(name string, levels int, readTimeout time.Duration) PriorityQueue {
+	if levels < 1 {
+		levels = 1
+	}
+
+	if readTimeout < time.Second {
+		readTimeout = time.Second
+	}
+
+	return PriorityQueue{
+		r:           &r,
+		name:        fmt.Sprintf("queue-%v", name),
+		levels:      levels,
+		readTimeout: readTimeout,
+		pops:        &atomic.Uint64{},
+	}
+}
+
+// WithFairness returns a copy of the queue, that prefers a lower
+// priority on every n-th pop. The preferred priority rotates through
+// all priorities below the highest one, from the highest to the lowest.
+// This guarantees, that no priority is starved by a steady flow of
+// higher priority elements. The pops are counted per client. A value
+// of 0 disables fairness
+func (q PriorityQueue) WithFairness(n uint64) PriorityQueue {
+	q.fairness = n
+	q.pops = &atomic.Uint64{}
+
+	return q
+}
+
+func (q PriorityQueue) Name() string {
+	return q.name
+}
+
+func (q PriorityQueue) Levels() int {
+	return q.levels
+}
+
+// Push adds an element with the lowest priority
+func (q PriorityQueue) Push(value string) error {
+	return q.PushPriority(value, 0)
+}
+
+// PushPriority adds an element with the given priority. Priorities
+// out of range are capped to the lowest and highest priority
+func (q PriorityQueue) PushPriority(value string, priority int) error {
+	if err := q.r.client.LPush(q.levelKey(priority), value).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to push value")
+	}
+
+	return nil
+}
+
+// Pop returns an element of the highest priority available. See
+// WithFairness on how to prevent starvation of lower priorities
+func (q PriorityQueue) Pop() (string, error) {
+	start := q.startLevel()
+
+	// the levels are ordered from start to the lowest
+	// one, followed by the highest ones down to start+1
+	keys := make([]string, 0, q.levels)
+	for i := 0; i < q.levels; i++ {
+		keys = append(keys, q.levelKey((start-i+q.levels)%q.levels))
+	}
+
+	// BRPOP returns an element of the first non-empty key
+	return popValue(q.r.client.BRPop(q.readTimeout, keys...))
+}
+
+// startLevel returns the priority, that is preferred by the next pop. That's
+// the highest priority, unless it's the n-th pop of a queue with fairness
+func (q PriorityQueue) startLevel() int {
+	highest := q.levels - 1
+	if q.fairness == 0 || highest == 0 {
+		return highest
+	}
+
+	pops := q.pops.Add(1)
+	if pops%q.fairness != 0 {
+		return highest
+	}
+
+	// rotate through the lower levels, starting at highest-1
+	turn := pops/q.fairness - 1
+
+	return highest - 1 - int(turn%uint64(highest))
+}
+
+// Empty removes all elements of all priorities
+func (q PriorityQueue) Empty() error {
+	keys := make([]string, 0, q.levels)
+	for p := 0; p < q.levels; p++ {
+		keys = append(keys, q.levelKey(p))
+	}
+
+	if err := q.r.client.Del(keys...).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to empty list")
+	}
+
+	return nil
+}
+
+func (q PriorityQueue) levelKey(priority int) string {
+	if priority < 0 {
+		priority = 0
+	}
+
+	if priority >= q.levels {
+		priority = q.levels - 1
+	}
+
+	return derivedKey(q.name, fmt.Sprintf("priority-%d", priority))
+}
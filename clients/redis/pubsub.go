@@ -0,0 +1,219 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	errPkg "github.com/tmeisel/glib/error"
+	"github.com/tmeisel/glib/exec/backoff"
+)
+
+const (
+	// DefaultSubscriptionBufferSize is the capacity of the message
+	// channel of a Subscription, if not configured otherwise
+	DefaultSubscriptionBufferSize = 100
+
+	// subscriptionReceiveTimeout is the interval, in which a
+	// subscription checks its context and its connection
+	subscriptionReceiveTimeout = time.Second
+)
+
+// Message is a message received by a Subscription. Pattern
+// is only set for subscriptions to patterns
+type Message struct {
+	Channel string
+	Pattern string
+	Payload string
+}
+
+// SubscriptionOptionFn configures a Subscription
+type SubscriptionOptionFn func(s *Subscription)
+
+// WithBufferSize sets the capacity of the message channel. If the
+// buffer is full, receiving from redis is paused until there is room
+func WithBufferSize(size int) SubscriptionOptionFn {
+	return func(s *Subscription) {
+		s.bufferSize = size
+	}
+}
+
+// WithReconnectBackoff sets the backoff to reconnect with after the
+// connection has been lost. fn is called for every reconnect, as
+// limits like backoff.WithMaxRetries are not reset between calls.
+// If the backoff gives up, the subscription is closed
+func WithReconnectBackoff(fn func() *backoff.Backoff) SubscriptionOptionFn {
+	return func(s *Subscription) {
+		s.backoffFn = fn
+	}
+}
+
+// Subscription delivers the messages published to the channels
+// or patterns it is subscribed to. It reconnects automatically,
+// but messages published while disconnected are lost
+type Subscription struct {
+	ps         *redis.PubSub
+	bufferSize int
+	backoffFn  func() *backoff.Backoff
+
+	messages chan Message
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// Publish sends message to all subscribers of channel and
+// returns the number of clients, that received it
+func (r Redis) Publish(channel, message string) (int64, error) {
+	n, err := r.client.Publish(channel, message).Result()
+	if err != nil {
+		return 0, errPkg.NewInternalMsg(err, "failed to publish message")
+	}
+
+	return n, nil
+}
+
+// Subscribe subscribes to the given channels. Messages are delivered
+// until the subscription is closed or ctx is done
+func (r Redis) Subscribe(ctx context.Context, channels []string, options ...SubscriptionOptionFn) (*Subscription, error) {
+	return newSubscription(ctx, r.client.Subscribe(channels...), options)
+}
+
+// PSubscribe subscribes to all channels matching the given glob-style
+// patterns. Messages are delivered until the subscription is closed
+// or ctx is done
+func (r Redis) PSubscribe(ctx context.Context, patterns []string, options ...SubscriptionOptionFn) (*Subscription, error) {
+	return newSubscription(ctx, r.client.PSubscribe(patterns...), options)
+}
+
+func newSubscription(ctx context.Context, ps *redis.PubSub, options []SubscriptionOptionFn) (*Subscription, error) {
+	s := &Subscription{
+		ps:         ps,
+		bufferSize: DefaultSubscriptionBufferSize,
+		backoffFn:  defaultReconnectBackoff,
+		done:       make(chan struct{}),
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	// wait for the confirmation, so errors
+	// are returned to the caller
+	if _, err := ps.ReceiveTimeout(subscriptionReceiveTimeout); err != nil {
+		_ = ps.Close()
+		return nil, errPkg.NewInternalMsg(err, "failed to subscribe")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.messages = make(chan Message, s.bufferSize)
+
+	go s.receive(ctx)
+
+	return s, nil
+}
+
+// Messages returns the channel messages are delivered on. It
+// is closed, once the subscription is closed
+func (s *Subscription) Messages() <-chan Message {
+	return s.messages
+}
+
+// Done returns a channel, that is closed once the subscription is closed
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close unsubscribes and waits until the message channel is closed
+func (s *Subscription) Close() error {
+	s.cancel()
+
+	// interrupts a pending receive. The error is
+	// ignored, as the connection might be closed
+	// already
+	_ = s.ps.Close()
+	<-s.done
+
+	return nil
+}
+
+func (s *Subscription) receive(ctx context.Context) {
+	defer close(s.done)
+	defer close(s.messages)
+	defer s.ps.Close()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		msg, err := s.ps.ReceiveTimeout(subscriptionReceiveTimeout)
+		if err != nil {
+			if isTimeout(err) {
+				// make sure the connection is still alive
+				err = s.ps.Ping()
+			}
+
+			if err != nil && !s.reconnect(ctx, err) {
+				return
+			}
+
+			continue
+		}
+
+		m, ok := msg.(*redis.Message)
+		if !ok {
+			// confirmations of (un)subscriptions and pongs
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case s.messages <- Message{Channel: m.Channel, Pattern: m.Pattern, Payload: m.Payload}:
+		}
+	}
+}
+
+// reconnect pings redis until the connection is reestablished. The
+// subscriptions are restored by redis.PubSub, once it reconnects.
+// It returns false, if ctx is done or the backoff gave up
+func (s *Subscription) reconnect(ctx context.Context, reason error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	logError(ctx, "subscription lost connection: %v", reason)
+
+	err := s.backoffFn().Do(ctx, func(ctx context.Context) error {
+		if err := s.ps.Ping(); err != nil {
+			return backoff.RetryableError(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if ctx.Err() == nil {
+			logError(ctx, "subscription failed to reconnect: %v", err)
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func defaultReconnectBackoff() *backoff.Backoff {
+	b := backoff.NewExponential(time.Millisecond * 100)
+	b.With(backoff.WithCap(time.Second * 5))
+
+	return b
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -3,6 +3,7 @@ package redis
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis"
@@ -15,11 +16,17 @@ type Queue struct {
 	r           *Redis
 	name        string
 	readTimeout time.Duration
+
+	batchObserver BatchObserverFn
 }
 
 // Queue initializes the client to connect to the queue with the given name.
 // readTimeout must be at least 1 second. If the given value is smaller,
-// it will be overwritten with time.Second
+// it will be overwritten with time.Second.
+//
+// The elements are stored under the key "queue-<name>". Additional keys,
+// e.g. of delayed elements, use the key as hash tag (see derivedKey), so
+// all keys of the queue are stored in the same slot of a cluster
 func (r Redis) Queue(name string, readTimeout time.Duration) Queue {
 	if readTimeout < time.Second {
 		readTimeout = time.Second
@@ -36,8 +43,10 @@ func (q Queue) Name() string {
 	return q.name
 }
 
+// Empty removes all elements from the queue,
+// including delayed ones
 func (q Queue) Empty() error {
-	if err := q.r.client.Del(q.name).Err(); err != nil {
+	if err := q.r.client.Del(q.name, q.delayedKey()).Err(); err != nil {
 		return errPkg.NewInternalMsg(err, "failed to empty list")
 	}
 
@@ -69,29 +78,38 @@ func (q Queue) RPush(value string) error {
 }
 
 func (q Queue) LPop() (string, error) {
-	res := q.r.client.BLPop(q.readTimeout, q.name)
+	return popValue(q.r.client.BLPop(q.readTimeout, q.name))
+}
 
-	if err := res.Err(); err != nil {
-		if errors.Is(err, redis.Nil) {
-			return "", queuePkg.ErrEmpty
-		}
+func (q Queue) RPop() (string, error) {
+	return popValue(q.r.client.BRPop(q.readTimeout, q.name))
+}
 
-		return "", err
+// derivedKey returns the key with the given suffix, that belongs to key.
+// key is used as hash tag, unless it contains one already. As a key without
+// hash tag is hashed as a whole, both keys are stored in the same slot
+func derivedKey(key, suffix string) string {
+	if hasHashTag(key) {
+		return fmt.Sprintf("%s-%s", key, suffix)
 	}
 
-	vals := res.Val()
-	if len(vals) != 2 {
-		return "", errPkg.NewInternalMsg(nil, "unexpected response from redis")
+	return fmt.Sprintf("{%s}-%s", key, suffix)
+}
+
+// hasHashTag returns true, if a non-empty part of key is enclosed in
+// braces. Only that part is hashed to determine the slot of the key
+func hasHashTag(key string) bool {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return false
 	}
 
-	// vals[0] is the name of the queue (aka the key)
-	// vals[1] is the actual value
-	return vals[1], nil
+	return strings.IndexByte(key[start+1:], '}') > 0
 }
 
-func (q Queue) RPop() (string, error) {
-	res := q.r.client.BRPop(q.readTimeout, q.name)
-
+// popValue returns the value of a blocking pop
+// response, which consists of the key and the value
+func popValue(res *redis.StringSliceCmd) (string, error) {
 	if err := res.Err(); err != nil {
 		if errors.Is(err, redis.Nil) {
 			return "", queuePkg.ErrEmpty
@@ -106,6 +124,7 @@ func (q Queue) RPop() (string, error) {
 	}
 
 	// vals[0] is the name of the queue (aka the key)
-	// vals[1] is the actual value
-	return vals[1], nil
+	// vals[1] is the actual value, that might have been
+	// wrapped by a ReliableQueue
+	return unwrapValue(vals[1]), nil
 }
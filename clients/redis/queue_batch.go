@@ -0,0 +1,195 @@
+package redis
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/go-redis/redis"
+
+	errPkg "github.com/tmeisel/glib/error"
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+// PushBatchChunkSize is the maximum number of values
+// sent with a single LPUSH by Queue.PushBatch
+const PushBatchChunkSize = 1000
+
+type BatchOp string
+
+const (
+	BatchOpPush = BatchOp("push")
+	BatchOpPop  = BatchOp("pop")
+)
+
+// BatchObserverFn is called with the size of every batch
+// pushed or popped, e.g. to record them in a histogram
+type BatchObserverFn func(op BatchOp, size int)
+
+var _ queuePkg.BatchQueue = Queue{}
+
+// WithBatchObserver returns a copy of the queue, that
+// reports the size of every batch to fn
+func (q Queue) WithBatchObserver(fn BatchObserverFn) Queue {
+	q.batchObserver = fn
+
+	return q
+}
+
+// PushBatch adds all values to the queue, preserving their order. The
+// values are sent in chunks of PushBatchChunkSize using a single pipeline.
+// The batch is not atomic: if an error is returned, some chunks might
+// have been pushed
+func (q Queue) PushBatch(values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	_, err := q.r.client.Pipelined(func(p redis.Pipeliner) error {
+		for start := 0; start < len(values); start += PushBatchChunkSize {
+			end := min(start+PushBatchChunkSize, len(values))
+
+			chunk := make([]interface{}, 0, end-start)
+			for _, value := range values[start:end] {
+				chunk = append(chunk, value)
+			}
+
+			p.LPush(q.name, chunk...)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to push values")
+	}
+
+	q.observe(BatchOpPush, len(values))
+
+	return nil
+}
+
+// PopBatch returns up to max elements from the queue. It blocks like Pop
+// until the first element is available and pops the remaining ones using
+// LMPOP without waiting for more. LMPOP requires redis 7 or later.
+//
+// If popping the remaining elements fails, the first element is pushed back
+// to the queue and the error is returned. If pushing it back fails as well,
+// the first element is returned together with an error wrapping both failures
+func (q Queue) PopBatch(max int) ([]string, error) {
+	if max < 1 {
+		return make([]string, 0), nil
+	}
+
+	first, err := q.RPop()
+	if err != nil {
+		return nil, err
+	}
+
+	values := []string{first}
+
+	if max > 1 {
+		more, err := q.lmpop(max - 1)
+		if err != nil {
+			// first has already been removed from the queue. It's
+			// pushed back to the consuming end, so it is not lost
+			if pushErr := q.RPush(first); pushErr != nil {
+				return values, errPkg.NewInternalMsg(errors.Join(err, pushErr), "failed to pop values and to push back the first one")
+			}
+
+			return nil, err
+		}
+
+		values = append(values, more...)
+	}
+
+	q.observe(BatchOpPop, len(values))
+
+	return values, nil
+}
+
+// lmpop pops up to count elements from the consuming end of the queue
+func (q Queue) lmpop(count int) ([]string, error) {
+	cmd := redis.NewSliceCmd("lmpop", 1, q.name, "right", "count", count)
+
+	if err := q.r.client.Process(cmd); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, errPkg.NewInternalMsg(err, "failed to pop values")
+	}
+
+	// the reply consists of the key and the popped values
+	reply := cmd.Val()
+	if len(reply) != 2 {
+		return nil, errPkg.NewInternalMsg(nil, "unexpected response from redis")
+	}
+
+	raw, ok := reply[1].([]interface{})
+	if !ok {
+		return nil, errPkg.NewInternalMsg(nil, "unexpected response from redis")
+	}
+
+	values := make([]string, 0, len(raw))
+	for _, value := range raw {
+		if s, ok := value.(string); ok {
+			values = append(values, unwrapValue(s))
+		}
+	}
+
+	return values, nil
+}
+
+func (q Queue) observe(op BatchOp, size int) {
+	if q.batchObserver != nil {
+		q.batchObserver(op, size)
+	}
+}
+
+// BatchStats collects the number and sizes of batches. Its
+// Observe method can be passed to Queue.WithBatchObserver
+type BatchStats struct {
+	pushBatches atomic.Int64
+	pushed      atomic.Int64
+	popBatches  atomic.Int64
+	popped      atomic.Int64
+}
+
+func (s *BatchStats) Observe(op BatchOp, size int) {
+	switch op {
+	case BatchOpPush:
+		s.pushBatches.Add(1)
+		s.pushed.Add(int64(size))
+	case BatchOpPop:
+		s.popBatches.Add(1)
+		s.popped.Add(int64(size))
+	}
+}
+
+// Pushed returns the number of batches and elements pushed
+func (s *BatchStats) Pushed() (batches, elements int64) {
+	return s.pushBatches.Load(), s.pushed.Load()
+}
+
+// Popped returns the number of batches and elements popped
+func (s *BatchStats) Popped() (batches, elements int64) {
+	return s.popBatches.Load(), s.popped.Load()
+}
+
+// AveragePushSize returns the average size of pushed batches
+func (s *BatchStats) AveragePushSize() float64 {
+	return average(s.Pushed())
+}
+
+// AveragePopSize returns the average size of popped batches
+func (s *BatchStats) AveragePopSize() float64 {
+	return average(s.Popped())
+}
+
+func average(batches, elements int64) float64 {
+	if batches == 0 {
+		return 0
+	}
+
+	return float64(elements) / float64(batches)
+}
@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	ctxPkg "github.com/tmeisel/glib/ctx"
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+// popInterval is the duration of a single blocking pop while waiting
+// for a context-aware pop. The client cannot interrupt a blocking
+// command, so the context is checked after each interval. It must
+// not be lower than a second, which is the minimum timeout of redis
+const popInterval = time.Second
+
+var _ queuePkg.ContextQueue = Queue{}
+
+func (q Queue) PushContext(ctx context.Context, value string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return q.Push(value)
+}
+
+func (q Queue) PopContext(ctx context.Context) (string, error) {
+	return q.RPopContext(ctx)
+}
+
+func (q Queue) EmptyContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return q.Empty()
+}
+
+// LPopContext works like LPop, but returns ctx.Err() within
+// a second, once ctx is done. The read timeout of the queue
+// is shortened to the deadline of ctx, if there is any
+func (q Queue) LPopContext(ctx context.Context) (string, error) {
+	return q.popContext(ctx, q.r.client.BLPop)
+}
+
+// RPopContext works like RPop, but returns ctx.Err() within
+// a second, once ctx is done. The read timeout of the queue
+// is shortened to the deadline of ctx, if there is any
+func (q Queue) RPopContext(ctx context.Context) (string, error) {
+	return q.popContext(ctx, q.r.client.BRPop)
+}
+
+type blockingPopFn func(timeout time.Duration, keys ...string) *redis.StringSliceCmd
+
+func (q Queue) popContext(ctx context.Context, pop blockingPopFn) (string, error) {
+	deadline := time.Now().Add(q.readTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		if !time.Now().Before(deadline) {
+			return "", queuePkg.ErrEmpty
+		}
+
+		value, err := popValue(pop(popInterval, q.name))
+		if errors.Is(err, queuePkg.ErrEmpty) {
+			continue
+		}
+
+		return value, err
+	}
+}
+
+// logError writes an error to the logger of ctx, if there is one
+func logError(ctx context.Context, format string, args ...interface{}) {
+	if logger := ctxPkg.GetLogger(ctx); logger != nil {
+		logger.Errorf(ctx, format, args...)
+	}
+}
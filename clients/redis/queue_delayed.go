@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/google/uuid"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+// PromoteBatchSize is the maximum number of due elements
+// moved into the queue by a single call to Queue.Promote
+const PromoteBatchSize = 100
+
+// promoteScript moves up to ARGV[2] members of the sorted set (KEYS[1]),
+// whose score is lower or equal to ARGV[1], into the queue (KEYS[2]).
+// Members are prefixed with a uuid (36 characters) and a colon, which
+// is stripped before pushing
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, member in ipairs(due) do
+	redis.call('ZREM', KEYS[1], member)
+	redis.call('LPUSH', KEYS[2], string.sub(member, 38))
+end
+return #due
+`)
+
+// PushAt adds an element to the queue, that will not be available
+// before the given time. If at is not in the future, the element
+// is pushed immediately. Delayed elements are moved into the queue
+// by Promote or RunPromoter
+func (q Queue) PushAt(value string, at time.Time) error {
+	if !at.After(time.Now()) {
+		return q.Push(value)
+	}
+
+	member := redis.Z{
+		Score:  float64(at.UnixMilli()),
+		Member: fmt.Sprintf("%s:%s", uuid.NewString(), value),
+	}
+
+	if err := q.r.client.ZAdd(q.delayedKey(), member).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to push delayed value")
+	}
+
+	return nil
+}
+
+// PushDelayed adds an element to the queue, that will not be
+// available before d elapsed. See PushAt
+func (q Queue) PushDelayed(value string, d time.Duration) error {
+	return q.PushAt(value, time.Now().Add(d))
+}
+
+// Delayed returns the number of elements that
+// are not yet due
+func (q Queue) Delayed() (int64, error) {
+	n, err := q.r.client.ZCard(q.delayedKey()).Result()
+	if err != nil {
+		return 0, errPkg.NewInternalMsg(err, "failed to count delayed values")
+	}
+
+	return n, nil
+}
+
+// Promote atomically moves up to PromoteBatchSize due elements into
+// the queue, in the order they became due. It returns the number of
+// elements that have been moved
+func (q Queue) Promote() (int, error) {
+	now := time.Now().UnixMilli()
+
+	n, err := promoteScript.Run(q.r.client, []string{q.delayedKey(), q.name}, now, PromoteBatchSize).Int()
+	if err != nil {
+		return 0, errPkg.NewInternalMsg(err, "failed to promote delayed values")
+	}
+
+	return n, nil
+}
+
+// RunPromoter calls Promote every interval, until ctx is cancelled.
+// If a batch is full, Promote is called again without waiting.
+// Errors are logged to the logger of ctx, but do not stop the promoter
+func (q Queue) RunPromoter(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				n, err := q.Promote()
+				if err != nil {
+					logError(ctx, "promoter of %s: %v", q.name, err)
+				}
+
+				if n < PromoteBatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (q Queue) delayedKey() string {
+	return derivedKey(q.name, "delayed")
+}
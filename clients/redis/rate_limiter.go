@@ -0,0 +1,137 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+// gcraScript implements the generic cell rate algorithm. KEYS[1] holds the
+// theoretical arrival time (TAT) of the next request in microseconds. ARGV[1]
+// is the emission interval and ARGV[2] the period, both in microseconds. The
+// time of the redis server is used, so the clocks of the clients don't matter.
+// Returns allowed (0/1), remaining, reset and retry after
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local newTat = tat + interval
+local allowAt = newTat - period
+if now < allowAt then
+	return {0, 0, tat - now, allowAt - now}
+end
+redis.call('SET', KEYS[1], newTat, 'PX', math.ceil((newTat - now) / 1000))
+return {1, math.floor((period - (newTat - now)) / interval), newTat - now, 0}
+`)
+
+// RateLimitResult describes the state of a limit after a request. It
+// can be converted to ratelimit.Result of the package
+// github.com/tmeisel/glib/net/http/middleware/ratelimit
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimiter allows limit requests per period and key. Requests are
+// spread evenly across the period using GCRA, while bursts of up to
+// limit requests are allowed, if the limit is fully available
+type RateLimiter struct {
+	r      *Redis
+	name   string
+	limit  int64
+	period time.Duration
+}
+
+// RateLimiter initializes a rate limiter with the given name. limit must be at
+// least 1 and period must be at least 1 millisecond. If the given values are
+// smaller, they will be overwritten with 1 and time.Millisecond.
+//
+// To use it with ratelimit.RateLimitMiddleware, wrap it using a
+// ratelimit.LimiterFunc:
+//
+//	ratelimit.LimiterFunc(func(ctx context.Context, key string) (ratelimit.Result, error) {
+//		res, err := limiter.Allow(ctx, key)
+//		return ratelimit.Result(res), err
+//	})
+func (r Redis) RateLimiter(name string, limit int64, period time.Duration) RateLimiter {
+	if limit < 1 {
+		limit = 1
+	}
+
+	if period < time.Millisecond {
+		period = time.Millisecond
+	}
+
+	return RateLimiter{
+		r:      &r,
+		name:   fmt.Sprintf("rate-limit-%v", name),
+		limit:  limit,
+		period: period,
+	}
+}
+
+func (l RateLimiter) Name() string {
+	return l.name
+}
+
+// Allow counts a request for key and reports whether it is allowed.
+// Denied requests are not counted
+func (l RateLimiter) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	if err := ctx.Err(); err != nil {
+		return RateLimitResult{}, err
+	}
+
+	period := l.period.Microseconds()
+	interval := max(period/l.limit, 1)
+
+	res, err := gcraScript.Run(l.r.client, []string{l.key(key)}, interval, period).Result()
+	if err != nil {
+		return RateLimitResult{}, errPkg.NewInternalMsg(err, "failed to check rate limit")
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 4 {
+		return RateLimitResult{}, errPkg.NewInternalMsg(nil, "unexpected response from redis")
+	}
+
+	ints := make([]int64, len(values))
+	for i, value := range values {
+		if ints[i], ok = value.(int64); !ok {
+			return RateLimitResult{}, errPkg.NewInternalMsg(nil, "unexpected response from redis")
+		}
+	}
+
+	return RateLimitResult{
+		Allowed:    ints[0] == 1,
+		Limit:      l.limit,
+		Remaining:  ints[1],
+		Reset:      time.Duration(ints[2]) * time.Microsecond,
+		RetryAfter: time.Duration(ints[3]) * time.Microsecond,
+	}, nil
+}
+
+// Reset makes the full limit available for key again
+func (l RateLimiter) Reset(key string) error {
+	if err := l.r.client.Del(l.key(key)).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to reset rate limit")
+	}
+
+	return nil
+}
+
+func (l RateLimiter) key(key string) string {
+	return fmt.Sprintf("%s-%s", l.name, key)
+}
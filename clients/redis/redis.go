@@ -1,32 +1,58 @@
 package redis
 
 import (
+	"net"
+
 	"github.com/go-redis/redis"
 
 	errPkg "github.com/tmeisel/glib/error"
 )
 
-type Config struct {
-	Addresses []string `envconfig:"ADDRESS" default:"localhost:6379"`
-	Database  int      `envconfig:"DATABASE" default:"0"`
-}
-
 type Redis struct {
-	client redis.Cmdable
+	client redis.UniversalClient
 }
 
 // New returns a new Redis client, but does not open a connection.
 // To test if the client is able to connect to redis, call
-// Redis.Ping
+// Redis.Ping. If the TLS configuration cannot be loaded, every
+// command fails with that error. Use NewClient to handle it instead
 func New(config Config) Redis {
-	var client redis.Cmdable
+	r, err := NewClient(config)
+	if err != nil {
+		return Redis{client: failingClient(err)}
+	}
 
-	client = redis.NewUniversalClient(&redis.UniversalOptions{
-		Addrs: config.Addresses,
-		DB:    config.Database,
-	})
+	return r
+}
+
+// NewClient works like New, but returns an error, if the TLS
+// configuration cannot be loaded. A cluster client is returned,
+// if Config.Cluster is set or multiple addresses are given without
+// Config.MasterName
+func NewClient(config Config) (Redis, error) {
+	opts, err := config.options()
+	if err != nil {
+		return Redis{}, err
+	}
 
-	return Redis{client: client}
+	if config.MasterName != "" {
+		return Redis{client: redis.NewUniversalClient(opts)}, nil
+	}
+
+	if config.Cluster || len(config.Addresses) > 1 {
+		return Redis{client: redis.NewClusterClient(clusterOptions(opts))}, nil
+	}
+
+	return Redis{client: redis.NewUniversalClient(opts)}, nil
+}
+
+// failingClient returns a client, that fails to connect with err
+func failingClient(err error) redis.UniversalClient {
+	return redis.NewClient(&redis.Options{
+		Dialer: func() (net.Conn, error) {
+			return nil, err
+		},
+	})
 }
 
 func (r Redis) Ping() error {
@@ -0,0 +1,382 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	errPkg "github.com/tmeisel/glib/error"
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+var (
+	ErrNotInFlight = errPkg.New(errPkg.CodeGone, "message is no longer in flight", nil)
+)
+
+// envelopePrefix marks elements, that wrap a value together with its number
+// of deliveries. Values are wrapped once they are received by a ReliableQueue
+const envelopePrefix = "\x00envelope:"
+
+// ackScript removes a single value from the processing list (KEYS[1])
+var ackScript = redis.NewScript(`
+return redis.call('LREM', KEYS[1], 1, ARGV[1])
+`)
+
+// deliverScript replaces a single value (ARGV[1]) of the processing
+// list (KEYS[1]) with the envelope (ARGV[2]) counting its deliveries
+var deliverScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+	return 0
+end
+redis.call('LPUSH', KEYS[1], ARGV[2])
+return 1
+`)
+
+// nackScript removes a single value from the processing list (KEYS[1])
+// and pushes it back to the queue (KEYS[2])
+var nackScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+	return 0
+end
+redis.call('LPUSH', KEYS[2], ARGV[1])
+return 1
+`)
+
+// requeueScript moves all values of the processing list (KEYS[2]) back
+// to the queue (KEYS[3]), if the lease of the consumer (KEYS[1]) expired.
+// The values are pushed to the consuming end of the queue, so they are
+// redelivered first. Finally, the consumer ARGV[1] is removed from the
+// set of consumers (KEYS[4])
+var requeueScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+local n = 0
+local v = redis.call('LPOP', KEYS[2])
+while v do
+	redis.call('RPUSH', KEYS[3], v)
+	n = n + 1
+	v = redis.call('LPOP', KEYS[2])
+end
+redis.call('SREM', KEYS[4], ARGV[1])
+return n
+`)
+
+// ReliableQueue is a Queue, that moves received elements into a
+// processing list of the consumer, until they're acknowledged
+type ReliableQueue struct {
+	Queue
+
+	consumer          string
+	visibilityTimeout time.Duration
+
+	deadLetter    string
+	maxDeliveries int
+}
+
+var _ queuePkg.ReliableQueue = ReliableQueue{}
+
+// ReliableQueue initializes the client to consume the queue with the given name
+// reliably. consumer must be unique across all consumers of the queue, e.g. the
+// hostname. If the consumer does neither receive nor acknowledge a message within
+// visibilityTimeout, all of its messages in flight are returned to the queue by
+// ReliableQueue.Requeue. visibilityTimeout must be greater than readTimeout. If it
+// isn't, it will be overwritten with twice the readTimeout
+func (r Redis) ReliableQueue(name, consumer string, readTimeout, visibilityTimeout time.Duration) ReliableQueue {
+	q := r.Queue(name, readTimeout)
+
+	if visibilityTimeout <= q.readTimeout {
+		visibilityTimeout = q.readTimeout * 2
+	}
+
+	return ReliableQueue{
+		Queue:             q,
+		consumer:          consumer,
+		visibilityTimeout: visibilityTimeout,
+		deadLetter:        derivedKey(q.name, "dead-letter"),
+	}
+}
+
+func (q ReliableQueue) Consumer() string {
+	return q.consumer
+}
+
+// Empty removes all elements from the queue, including
+// the messages in flight of all consumers
+func (q ReliableQueue) Empty() error {
+	consumers, err := q.r.client.SMembers(q.consumersKey()).Result()
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to list consumers")
+	}
+
+	keys := []string{q.name, q.delayedKey(), q.consumersKey()}
+	for _, consumer := range consumers {
+		keys = append(keys, q.processingKeyOf(consumer), q.leaseKey(consumer))
+	}
+
+	if err := q.r.client.Del(keys...).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to empty list")
+	}
+
+	return nil
+}
+
+// Receive blocks until an element is available or the read timeout
+// is exceeded. The element is moved atomically into the processing list
+// of the consumer, where it stays until it is acknowledged or rejected.
+// If the maximum number of deliveries is exceeded, the element is moved
+// to the dead-letter queue and Receive waits for the next one.
+//
+// The returned queuePkg.Message implements queuePkg.DeadLetterMessage
+func (q ReliableQueue) Receive() (queuePkg.Message, error) {
+	for {
+		msg, err := q.receive()
+		if err != nil {
+			return nil, err
+		}
+
+		if q.maxDeliveries > 0 && msg.deliveries > q.maxDeliveries {
+			if err := msg.DeadLetter(queuePkg.ErrMaxDeliveries); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		return msg, nil
+	}
+}
+
+// receive moves a single element into the processing list and
+// increments its number of deliveries. The number of deliveries is
+// stored in an envelope, that replaces the element. It stays with the
+// element, when it is rejected or requeued.
+//
+// BRPOPLPUSH is used instead of BLMOVE, as the client does not support
+// the latter. Both are equivalent for moving from right to left
+func (q ReliableQueue) receive() (message, error) {
+	if err := q.touch(); err != nil {
+		return message{}, err
+	}
+
+	raw, err := q.r.client.BRPopLPush(q.name, q.processingKey(), q.readTimeout).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return message{}, queuePkg.ErrEmpty
+		}
+
+		return message{}, errPkg.NewInternalMsg(err, "failed to receive value")
+	}
+
+	// the lease is renewed after receiving, as the blocking
+	// call might have consumed most of it
+	if err := q.touch(); err != nil {
+		return message{}, err
+	}
+
+	env := decodeEnvelope(raw)
+	env.Deliveries++
+
+	encoded, err := env.encode()
+	if err != nil {
+		return message{}, err
+	}
+
+	n, err := deliverScript.Run(q.r.client, []string{q.processingKey()}, raw, encoded).Int()
+	if err != nil {
+		return message{}, errPkg.NewInternalMsg(err, "failed to count deliveries")
+	}
+
+	if n == 0 {
+		return message{}, ErrNotInFlight
+	}
+
+	return message{q: q, raw: encoded, value: env.Value, deliveries: env.Deliveries}, nil
+}
+
+// Requeue returns the messages in flight of all consumers, whose
+// visibility timeout expired, to the queue
+func (q ReliableQueue) Requeue() (int, error) {
+	consumers, err := q.r.client.SMembers(q.consumersKey()).Result()
+	if err != nil {
+		return 0, errPkg.NewInternalMsg(err, "failed to list consumers")
+	}
+
+	var total int
+	for _, consumer := range consumers {
+		keys := []string{
+			q.leaseKey(consumer),
+			q.processingKeyOf(consumer),
+			q.name,
+			q.consumersKey(),
+		}
+
+		n, err := requeueScript.Run(q.r.client, keys, consumer).Int()
+		if err != nil {
+			return total, errPkg.NewInternalMsg(err, "failed to requeue values")
+		}
+
+		total += n
+	}
+
+	return total, nil
+}
+
+// RunReaper calls Requeue every interval, until ctx is cancelled.
+// Errors are logged to the logger of ctx, but do not stop the reaper
+func (q ReliableQueue) RunReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := q.Requeue(); err != nil {
+				logError(ctx, "reaper of %s: %v", q.name, err)
+			}
+		}
+	}
+}
+
+// InFlight returns the number of messages the consumer
+// received, but not yet acknowledged or rejected
+func (q ReliableQueue) InFlight() (int64, error) {
+	n, err := q.r.client.LLen(q.processingKey()).Result()
+	if err != nil {
+		return 0, errPkg.NewInternalMsg(err, "failed to count values in flight")
+	}
+
+	return n, nil
+}
+
+func (q ReliableQueue) ack(raw string) error {
+	n, err := ackScript.Run(q.r.client, []string{q.processingKey()}, raw).Int()
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to acknowledge value")
+	}
+
+	if n == 0 {
+		return ErrNotInFlight
+	}
+
+	return q.touch()
+}
+
+func (q ReliableQueue) nack(raw string) error {
+	n, err := nackScript.Run(q.r.client, []string{q.processingKey(), q.name}, raw).Int()
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to reject value")
+	}
+
+	if n == 0 {
+		return ErrNotInFlight
+	}
+
+	return q.touch()
+}
+
+// touch registers the consumer and renews its lease
+func (q ReliableQueue) touch() error {
+	_, err := q.r.client.Pipelined(func(p redis.Pipeliner) error {
+		p.SAdd(q.consumersKey(), q.consumer)
+		p.Set(q.leaseKey(q.consumer), 1, q.visibilityTimeout)
+
+		return nil
+	})
+
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to renew lease")
+	}
+
+	return nil
+}
+
+func (q ReliableQueue) processingKey() string {
+	return q.processingKeyOf(q.consumer)
+}
+
+func (q ReliableQueue) processingKeyOf(consumer string) string {
+	return derivedKey(q.name, "processing-"+consumer)
+}
+
+func (q ReliableQueue) leaseKey(consumer string) string {
+	return derivedKey(q.name, "lease-"+consumer)
+}
+
+func (q ReliableQueue) consumersKey() string {
+	return derivedKey(q.name, "consumers")
+}
+
+// envelope wraps a value together with its number of deliveries
+type envelope struct {
+	Value      string `json:"value"`
+	Deliveries int    `json:"deliveries"`
+}
+
+func (e envelope) encode() (string, error) {
+	encoded, err := json.Marshal(e)
+	if err != nil {
+		return "", errPkg.NewInternalMsg(err, "failed to encode envelope")
+	}
+
+	return envelopePrefix + string(encoded), nil
+}
+
+// decodeEnvelope returns the envelope of raw. If raw is a plain
+// value, it is returned in an envelope without any deliveries
+func decodeEnvelope(raw string) envelope {
+	encoded, ok := strings.CutPrefix(raw, envelopePrefix)
+	if !ok {
+		return envelope{Value: raw}
+	}
+
+	var env envelope
+	if err := json.Unmarshal([]byte(encoded), &env); err != nil {
+		return envelope{Value: raw}
+	}
+
+	return env
+}
+
+// unwrapValue returns the value of raw,
+// which might be wrapped in an envelope
+func unwrapValue(raw string) string {
+	return decodeEnvelope(raw).Value
+}
+
+type message struct {
+	q ReliableQueue
+
+	// raw is the element in the processing list
+	raw        string
+	value      string
+	deliveries int
+}
+
+var _ queuePkg.DeadLetterMessage = message{}
+
+func (m message) Value() string {
+	return m.value
+}
+
+func (m message) Ack() error {
+	return m.q.ack(m.raw)
+}
+
+func (m message) Nack() error {
+	return m.q.nack(m.raw)
+}
+
+func (m message) Deliveries() int {
+	return m.deliveries
+}
+
+func (m message) DeadLetter(reason error) error {
+	return m.q.deadLetterValue(m.raw, m.value, reason, m.deliveries)
+}
@@ -0,0 +1,308 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	errPkg "github.com/tmeisel/glib/error"
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+// streamField is the field of a stream entry holding the value
+const streamField = "value"
+
+// Stream is a queue backed by a redis stream, that is consumed
+// by consumer groups
+type Stream struct {
+	r           *Redis
+	name        string
+	group       string
+	consumer    string
+	readTimeout time.Duration
+	maxLen      int64
+}
+
+var _ queuePkg.StreamQueue = Stream{}
+
+// Stream initializes the client to consume the stream with the given name
+// as consumer of the given consumer group. The group is created on first
+// use and starts with the oldest entry of the stream. consumer must be
+// unique within the group. readTimeout must be at least 1 second. If the
+// given value is smaller, it will be overwritten with time.Second
+func (r Redis) Stream(name, group, consumer string, readTimeout time.Duration) Stream {
+	if readTimeout < time.Second {
+		readTimeout = time.Second
+	}
+
+	return Stream{
+		r:           &r,
+		name:        fmt.Sprintf("stream-%v", name),
+		group:       group,
+		consumer:    consumer,
+		readTimeout: readTimeout,
+	}
+}
+
+// WithMaxLen returns a copy of the stream, that trims the stream
+// to approximately maxLen entries on every push. A maxLen of 0
+// disables trimming
+func (s Stream) WithMaxLen(maxLen int64) Stream {
+	s.maxLen = maxLen
+
+	return s
+}
+
+func (s Stream) Name() string {
+	return s.name
+}
+
+func (s Stream) Group() string {
+	return s.group
+}
+
+func (s Stream) Consumer() string {
+	return s.consumer
+}
+
+// Push appends an element to the stream
+func (s Stream) Push(value string) error {
+	args := &redis.XAddArgs{
+		Stream:       s.name,
+		MaxLenApprox: s.maxLen,
+		Values:       map[string]interface{}{streamField: value},
+	}
+
+	if err := s.r.client.XAdd(args).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to push value")
+	}
+
+	return nil
+}
+
+// Pop receives a single element and acknowledges it immediately
+func (s Stream) Pop() (string, error) {
+	msg, err := s.Receive()
+	if err != nil {
+		return "", err
+	}
+
+	if err := msg.Ack(); err != nil {
+		return "", err
+	}
+
+	return msg.Value(), nil
+}
+
+// Empty removes the stream, including all of its consumer groups
+func (s Stream) Empty() error {
+	if err := s.r.client.Del(s.name).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to empty stream")
+	}
+
+	return nil
+}
+
+// Receive blocks until an element is available, that has not yet been
+// delivered to the consumer group, or the read timeout is exceeded.
+//
+// The returned queuePkg.Message implements queuePkg.StreamMessage
+func (s Stream) Receive() (queuePkg.Message, error) {
+	res, err := s.readGroup()
+	if isNoGroupErr(err) {
+		if err := s.CreateGroup("0"); err != nil {
+			return nil, err
+		}
+
+		res, err = s.readGroup()
+	}
+
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, queuePkg.ErrEmpty
+		}
+
+		return nil, errPkg.NewInternalMsg(err, "failed to receive value")
+	}
+
+	if len(res) != 1 || len(res[0].Messages) != 1 {
+		return nil, errPkg.NewInternalMsg(nil, "unexpected response from redis")
+	}
+
+	return s.message(res[0].Messages[0].ID, res[0].Messages[0].Values), nil
+}
+
+// Pending returns up to count messages of the consumer group,
+// that have been delivered, but not yet acknowledged
+func (s Stream) Pending(count int64) ([]queuePkg.PendingMessage, error) {
+	res, err := s.r.client.XPendingExt(&redis.XPendingExtArgs{
+		Stream: s.name,
+		Group:  s.group,
+		Start:  "-",
+		End:    "+",
+		Count:  count,
+	}).Result()
+
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, errPkg.NewInternalMsg(err, "failed to list pending messages")
+	}
+
+	output := make([]queuePkg.PendingMessage, 0, len(res))
+	for _, p := range res {
+		output = append(output, queuePkg.PendingMessage{
+			ID:         p.Id,
+			Consumer:   p.Consumer,
+			Idle:       p.Idle,
+			Deliveries: p.RetryCount,
+		})
+	}
+
+	return output, nil
+}
+
+// Claim transfers up to count pending messages, that have been idle
+// for at least minIdle, to the consumer using XAUTOCLAIM
+func (s Stream) Claim(minIdle time.Duration, count int64) ([]queuePkg.Message, error) {
+	cmd := redis.NewSliceCmd(
+		"xautoclaim",
+		s.name,
+		s.group,
+		s.consumer,
+		int64(minIdle/time.Millisecond),
+		"0-0",
+		"count",
+		count,
+	)
+
+	if err := s.r.client.Process(cmd); err != nil {
+		return nil, errPkg.NewInternalMsg(err, "failed to claim messages")
+	}
+
+	// the reply consists of the next id to scan, the claimed
+	// entries and (since redis 7) the ids of deleted entries
+	reply := cmd.Val()
+	if len(reply) < 2 {
+		return nil, errPkg.NewInternalMsg(nil, "unexpected response from redis")
+	}
+
+	entries, ok := reply[1].([]interface{})
+	if !ok {
+		return nil, errPkg.NewInternalMsg(nil, "unexpected response from redis")
+	}
+
+	output := make([]queuePkg.Message, 0, len(entries))
+	for _, entry := range entries {
+		// entries deleted in the meantime are nil before redis 7
+		parts, ok := entry.([]interface{})
+		if !ok || len(parts) != 2 {
+			continue
+		}
+
+		id, _ := parts[0].(string)
+		fields, _ := parts[1].([]interface{})
+
+		values := make(map[string]interface{}, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			if key, ok := fields[i].(string); ok {
+				values[key] = fields[i+1]
+			}
+		}
+
+		output = append(output, s.message(id, values))
+	}
+
+	return output, nil
+}
+
+// CreateGroup creates the consumer group, starting at the given id.
+// Use "0" to consume the whole stream and "$" to consume only new
+// entries. It does not fail, if the group already exists
+func (s Stream) CreateGroup(start string) error {
+	err := s.r.client.XGroupCreateMkStream(s.name, s.group, start).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return errPkg.NewInternalMsg(err, "failed to create consumer group")
+	}
+
+	return nil
+}
+
+// SetGroupID sets the last delivered id of the consumer group. Use
+// "0" to replay the whole stream
+func (s Stream) SetGroupID(id string) error {
+	if err := s.r.client.XGroupSetID(s.name, s.group, id).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to set id of consumer group")
+	}
+
+	return nil
+}
+
+// DeleteConsumer removes the consumer from the consumer group.
+// Its pending messages are discarded
+func (s Stream) DeleteConsumer() error {
+	if err := s.r.client.XGroupDelConsumer(s.name, s.group, s.consumer).Err(); err != nil {
+		return errPkg.NewInternalMsg(err, "failed to delete consumer")
+	}
+
+	return nil
+}
+
+func (s Stream) readGroup() ([]redis.XStream, error) {
+	return s.r.client.XReadGroup(&redis.XReadGroupArgs{
+		Group:    s.group,
+		Consumer: s.consumer,
+		Streams:  []string{s.name, ">"},
+		Count:    1,
+		Block:    s.readTimeout,
+	}).Result()
+}
+
+func (s Stream) message(id string, values map[string]interface{}) streamMessage {
+	value, _ := values[streamField].(string)
+
+	return streamMessage{s: s, id: id, value: value}
+}
+
+func isNoGroupErr(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOGROUP")
+}
+
+type streamMessage struct {
+	s     Stream
+	id    string
+	value string
+}
+
+var _ queuePkg.StreamMessage = streamMessage{}
+
+func (m streamMessage) ID() string {
+	return m.id
+}
+
+func (m streamMessage) Value() string {
+	return m.value
+}
+
+// Ack acknowledges the message for the consumer group
+func (m streamMessage) Ack() error {
+	n, err := m.s.r.client.XAck(m.s.name, m.s.group, m.id).Result()
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to acknowledge message")
+	}
+
+	if n == 0 {
+		return ErrNotInFlight
+	}
+
+	return nil
+}
+
+// Nack leaves the message pending and returns nil. A stream can not
+// return an entry to a consumer group without delivering it to all
+// other groups again. The message is delivered again, once it is
+// claimed by a consumer of the group via Stream.Claim
+func (m streamMessage) Nack() error {
+	return nil
+}
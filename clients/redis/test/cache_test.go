@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tmeisel/glib/clients/redis"
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+type cacheItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestCache_GetSetDelete(t *testing.T) {
+	cache := client.Cache(uuid.NewString())
+
+	_, err := redis.Get[cacheItem](cache, "a")
+	require.ErrorIs(t, err, redis.ErrCacheMiss)
+	assert.True(t, errPkg.IsErrNotFound(err))
+
+	item := cacheItem{Name: "a", Count: 1}
+	require.NoError(t, cache.Set("a", item, time.Minute))
+
+	got, err := redis.Get[cacheItem](cache, "a")
+	require.NoError(t, err)
+	assert.Equal(t, item, got)
+
+	// keys of different caches do not collide
+	_, err = redis.Get[cacheItem](client.Cache(uuid.NewString()), "a")
+	require.ErrorIs(t, err, redis.ErrCacheMiss)
+
+	require.NoError(t, cache.Delete("a"))
+
+	_, err = redis.Get[cacheItem](cache, "a")
+	require.ErrorIs(t, err, redis.ErrCacheMiss)
+}
+
+func TestGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	cache := client.Cache(uuid.NewString())
+
+	var calls atomic.Int32
+	load := func(ctx context.Context) (cacheItem, error) {
+		calls.Add(1)
+		time.Sleep(time.Millisecond * 50)
+
+		return cacheItem{Name: "loaded"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			item, err := redis.GetOrLoad(ctx, cache, "key", time.Minute, load)
+			assert.NoError(t, err)
+			assert.Equal(t, "loaded", item.Name)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+
+	item, err := redis.GetOrLoad(ctx, cache, "key", time.Minute, load)
+	require.NoError(t, err)
+	assert.Equal(t, "loaded", item.Name)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestGetOrLoad_Error(t *testing.T) {
+	ctx := context.Background()
+	cache := client.Cache(uuid.NewString())
+	loadErr := errors.New("failed")
+
+	_, err := redis.GetOrLoad(ctx, cache, "key", time.Minute, func(ctx context.Context) (cacheItem, error) {
+		return cacheItem{}, loadErr
+	})
+	require.ErrorIs(t, err, loadErr)
+
+	_, err = redis.Get[cacheItem](cache, "key")
+	require.ErrorIs(t, err, redis.ErrCacheMiss)
+}
+
+func TestGetOrLoad_Types(t *testing.T) {
+	ctx := context.Background()
+	cache := client.Cache(uuid.NewString())
+
+	loading := make(chan struct{})
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		item, err := redis.GetOrLoad(ctx, cache, "key", time.Minute, func(ctx context.Context) (cacheItem, error) {
+			close(loading)
+			<-release
+
+			return cacheItem{Name: "item"}, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "item", item.Name)
+	}()
+
+	<-loading
+
+	// a concurrent call with another type must not wait for the running load
+	timeout, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	count, err := redis.GetOrLoad(timeout, cache, "key", time.Minute, func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestGetOrLoad_EarlyRefresh(t *testing.T) {
+	ctx := context.Background()
+	cache := client.Cache(uuid.NewString()).WithEarlyRefresh(10000)
+
+	var calls atomic.Int32
+	load := func(ctx context.Context) (int32, error) {
+		time.Sleep(time.Millisecond * 10)
+		return calls.Add(1), nil
+	}
+
+	_, err := redis.GetOrLoad(ctx, cache, "key", time.Second, load)
+	require.NoError(t, err)
+
+	// a load delta of 10ms times a beta of 10000 refreshes
+	// an entry with 1 second left with a probability of 99%
+	for i := 0; i < 10; i++ {
+		_, err = redis.GetOrLoad(ctx, cache, "key", time.Second, load)
+		require.NoError(t, err)
+	}
+
+	assert.Greater(t, calls.Load(), int32(1))
+
+	// without early refresh, the entry is used until it expires
+	before := calls.Load()
+
+	_, err = redis.GetOrLoad(ctx, cache.WithEarlyRefresh(0), "key", time.Second, load)
+	require.NoError(t, err)
+	assert.Equal(t, before, calls.Load())
+}
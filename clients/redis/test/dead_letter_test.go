@@ -0,0 +1,106 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	errPkg "github.com/tmeisel/glib/error"
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+func TestReliableQueue_MaxDeliveries(t *testing.T) {
+	queue := client.ReliableQueue(uuid.NewString(), uuid.NewString(), time.Second, time.Second*5).
+		WithDeadLetter("", 2)
+	require.Implements(t, (*queuePkg.DeadLetterQueue)(nil), queue)
+
+	require.NoError(t, queue.Push("poison"))
+
+	for i := 1; i <= 2; i++ {
+		msg, err := queue.Receive()
+		require.NoError(t, err)
+		assert.Equal(t, i, msg.(queuePkg.DeadLetterMessage).Deliveries())
+		require.NoError(t, msg.Nack())
+	}
+
+	// third delivery exceeds the limit
+	_, err := queue.Receive()
+	require.ErrorIs(t, err, queuePkg.ErrEmpty)
+
+	dls, err := queue.DeadLetters(0, 10)
+	require.NoError(t, err)
+	require.Len(t, dls, 1)
+	assert.Equal(t, "poison", dls[0].Value)
+	assert.Equal(t, errPkg.CodeGone, dls[0].Code)
+	assert.Equal(t, 3, dls[0].Deliveries)
+
+	require.NoError(t, queue.Purge())
+
+	dls, err = queue.DeadLetters(0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, dls)
+}
+
+func TestReliableQueue_MaxDeliveriesDuplicates(t *testing.T) {
+	queue := client.ReliableQueue(uuid.NewString(), uuid.NewString(), time.Second, time.Second*5).
+		WithDeadLetter("", 2)
+
+	// equal payloads count their deliveries separately
+	require.NoError(t, queue.Push("duplicate"))
+	require.NoError(t, queue.Push("duplicate"))
+
+	var attempts int
+	for {
+		msg, err := queue.Receive()
+		if err != nil {
+			require.ErrorIs(t, err, queuePkg.ErrEmpty)
+			break
+		}
+
+		assert.Equal(t, "duplicate", msg.Value())
+		require.NoError(t, msg.Nack())
+		attempts++
+	}
+
+	assert.Equal(t, 4, attempts)
+
+	dls, err := queue.DeadLetters(0, 10)
+	require.NoError(t, err)
+	require.Len(t, dls, 2)
+
+	for _, dl := range dls {
+		assert.Equal(t, "duplicate", dl.Value)
+		assert.Equal(t, 3, dl.Deliveries)
+	}
+}
+
+func TestReliableQueue_DeadLetter(t *testing.T) {
+	queue := client.ReliableQueue(uuid.NewString(), uuid.NewString(), time.Second, time.Second*5).
+		WithDeadLetter(uuid.NewString(), 0)
+
+	require.NoError(t, queue.Push("invalid"))
+
+	msg, err := queue.Receive()
+	require.NoError(t, err)
+	require.NoError(t, msg.(queuePkg.DeadLetterMessage).DeadLetter(errPkg.NewUserMsg(nil, "invalid payload")))
+
+	dls, err := queue.DeadLetters(0, 10)
+	require.NoError(t, err)
+	require.Len(t, dls, 1)
+	assert.Equal(t, errPkg.CodeUser, dls[0].Code)
+	assert.Equal(t, "invalid payload", dls[0].Reason)
+
+	n, err := queue.Replay(10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	msg, err = queue.Receive()
+	require.NoError(t, err)
+	assert.Equal(t, "invalid", msg.Value())
+	assert.Equal(t, 1, msg.(queuePkg.DeadLetterMessage).Deliveries())
+	require.NoError(t, msg.Ack())
+}
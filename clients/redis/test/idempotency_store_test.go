@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tmeisel/glib/clients/redis"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	store := client.IdempotencyStore(uuid.NewString())
+
+	fingerprint, response, err := store.Reserve(ctx, "key", "a", time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, fingerprint)
+	assert.Nil(t, response)
+
+	// in progress
+	fingerprint, response, err = store.Reserve(ctx, "key", "b", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "a", fingerprint)
+	assert.Nil(t, response)
+
+	require.ErrorIs(t, store.Complete(ctx, "key", "b", []byte("response"), time.Hour), redis.ErrNotReserved)
+	require.NoError(t, store.Complete(ctx, "key", "a", []byte("response"), time.Hour))
+
+	fingerprint, response, err = store.Reserve(ctx, "key", "a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "a", fingerprint)
+	assert.Equal(t, []byte("response"), response)
+
+	// completed keys are not released
+	require.NoError(t, store.Release(ctx, "key", "a"))
+
+	fingerprint, _, err = store.Reserve(ctx, "key", "a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "a", fingerprint)
+}
+
+func TestIdempotencyStore_Release(t *testing.T) {
+	ctx := context.Background()
+	store := client.IdempotencyStore(uuid.NewString())
+
+	_, _, err := store.Reserve(ctx, "key", "a", time.Minute)
+	require.NoError(t, err)
+
+	// only the holder of the reservation can release it
+	require.NoError(t, store.Release(ctx, "key", "b"))
+
+	fingerprint, _, err := store.Reserve(ctx, "key", "b", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "a", fingerprint)
+
+	require.NoError(t, store.Release(ctx, "key", "a"))
+
+	fingerprint, _, err = store.Reserve(ctx, "key", "b", time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, fingerprint)
+}
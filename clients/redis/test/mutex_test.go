@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tmeisel/glib/clients/redis"
+	"github.com/tmeisel/glib/exec/backoff"
+)
+
+func TestMutex_TryLock(t *testing.T) {
+	ctx := context.Background()
+	m := client.Mutex(uuid.NewString(), time.Second)
+
+	lock, err := m.TryLock(ctx)
+	require.NoError(t, err)
+
+	_, err = m.TryLock(ctx)
+	require.ErrorIs(t, err, redis.ErrLockHeld)
+
+	require.NoError(t, lock.Unlock())
+
+	select {
+	case <-lock.Done():
+	default:
+		t.Fatal("done is not closed after unlock")
+	}
+
+	require.ErrorIs(t, lock.Unlock(), redis.ErrLockNotHeld)
+
+	lock, err = m.TryLock(ctx)
+	require.NoError(t, err)
+	require.NoError(t, lock.Unlock())
+}
+
+func TestMutex_Lock(t *testing.T) {
+	ctx := context.Background()
+	m := client.Mutex(uuid.NewString(), time.Second).
+		WithBackoff(func() *backoff.Backoff {
+			return backoff.NewConstant(time.Millisecond * 10)
+		})
+
+	var (
+		wg      sync.WaitGroup
+		holders atomic.Int32
+		overlap atomic.Bool
+	)
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			lock, err := m.Lock(ctx)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if holders.Add(1) > 1 {
+				overlap.Store(true)
+			}
+
+			time.Sleep(time.Millisecond * 20)
+			holders.Add(-1)
+
+			assert.NoError(t, lock.Unlock())
+		}()
+	}
+
+	wg.Wait()
+
+	assert.False(t, overlap.Load(), "lock was held concurrently")
+}
+
+func TestMutex_LockGivesUp(t *testing.T) {
+	ctx := context.Background()
+	name := uuid.NewString()
+
+	lock, err := client.Mutex(name, time.Second).TryLock(ctx)
+	require.NoError(t, err)
+	defer lock.Unlock()
+
+	var calls int
+
+	m := client.Mutex(name, time.Second).WithBackoff(func() *backoff.Backoff {
+		calls++
+
+		b, err := backoff.New(backoff.Constant, time.Millisecond*10, backoff.WithMaxRetries(3))
+		require.NoError(t, err)
+
+		return b
+	})
+
+	// every call uses a fresh backoff, so both retry until the limit
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+
+		_, err = m.Lock(ctx)
+		require.ErrorIs(t, err, redis.ErrLockHeld)
+		assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*30)
+	}
+
+	assert.Equal(t, 2, calls)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*50)
+	defer cancel()
+
+	_, err = client.Mutex(name, time.Second).Lock(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMutex_Extension(t *testing.T) {
+	ctx := context.Background()
+	m := client.Mutex(uuid.NewString(), time.Millisecond*300)
+
+	lock, err := m.TryLock(ctx)
+	require.NoError(t, err)
+
+	// the lock outlives its ttl while held
+	time.Sleep(time.Millisecond * 700)
+
+	_, err = m.TryLock(ctx)
+	require.ErrorIs(t, err, redis.ErrLockHeld)
+	require.NoError(t, lock.Extend())
+
+	require.NoError(t, lock.Unlock())
+}
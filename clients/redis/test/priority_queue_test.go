@@ -0,0 +1,89 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	queuePkg "github.com/tmeisel/glib/queue"
+	"github.com/tmeisel/glib/queue/queuetest"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	queue := client.PriorityQueue(uuid.NewString(), 3, time.Second)
+	require.Implements(t, (*queuePkg.PriorityQueue)(nil), queue)
+}
+
+func TestPriorityQueue_Conformance(t *testing.T) {
+	queuetest.Run(t, func(t *testing.T) queuePkg.Queue {
+		return client.PriorityQueue(uuid.NewString(), 3, time.Second)
+	})
+}
+
+func TestPriorityQueue_Pop(t *testing.T) {
+	queue := client.PriorityQueue(uuid.NewString(), 3, time.Second)
+
+	require.NoError(t, queue.PushPriority("low", 0))
+	require.NoError(t, queue.PushPriority("high", 2))
+	require.NoError(t, queue.PushPriority("medium", 1))
+	// capped to the highest priority
+	require.NoError(t, queue.PushPriority("urgent", 10))
+
+	for _, expected := range []string{"high", "urgent", "medium", "low"} {
+		val, err := queue.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+
+	_, err := queue.Pop()
+	require.ErrorIs(t, err, queuePkg.ErrEmpty)
+}
+
+func TestPriorityQueue_WithFairness(t *testing.T) {
+	queue := client.PriorityQueue(uuid.NewString(), 2, time.Second).WithFairness(3)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, queue.PushPriority("high", 1))
+	}
+	require.NoError(t, queue.PushPriority("low", 0))
+
+	var popped []string
+	for i := 0; i < 3; i++ {
+		val, err := queue.Pop()
+		require.NoError(t, err)
+		popped = append(popped, val)
+	}
+
+	// every third pop prefers the lowest priority
+	assert.Equal(t, []string{"high", "high", "low"}, popped)
+
+	require.NoError(t, queue.Empty())
+}
+
+func TestPriorityQueue_WithFairnessRotates(t *testing.T) {
+	queue := client.PriorityQueue(uuid.NewString(), 3, time.Second).WithFairness(2)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, queue.PushPriority("high", 2))
+	}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, queue.PushPriority("medium", 1))
+		require.NoError(t, queue.PushPriority("low", 0))
+	}
+
+	var popped []string
+	for i := 0; i < 8; i++ {
+		val, err := queue.Pop()
+		require.NoError(t, err)
+		popped = append(popped, val)
+	}
+
+	// every second pop prefers the next lower priority in turn
+	assert.Equal(t, []string{"high", "medium", "high", "low", "high", "medium", "high", "low"}, popped)
+
+	require.NoError(t, queue.Empty())
+}
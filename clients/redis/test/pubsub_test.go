@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tmeisel/glib/clients/redis"
+)
+
+func TestRedis_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	channel := uuid.NewString()
+
+	sub, err := client.Subscribe(ctx, []string{channel})
+	require.NoError(t, err)
+
+	n, err := client.Publish(channel, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	msg := receiveMessage(t, sub)
+	assert.Equal(t, redis.Message{Channel: channel, Payload: "hello"}, msg)
+
+	require.NoError(t, sub.Close())
+
+	_, ok := <-sub.Messages()
+	assert.False(t, ok, "messages is not closed")
+
+	// the server notices the closed connection asynchronously
+	assert.Eventually(t, func() bool {
+		n, err := client.Publish(channel, "hello")
+		return err == nil && n == 0
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func TestRedis_PSubscribe(t *testing.T) {
+	ctx := context.Background()
+	prefix := uuid.NewString()
+
+	sub, err := client.PSubscribe(ctx, []string{prefix + ".*"}, redis.WithBufferSize(1))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	_, err = client.Publish(prefix+".config", "changed")
+	require.NoError(t, err)
+
+	msg := receiveMessage(t, sub)
+	assert.Equal(t, prefix+".config", msg.Channel)
+	assert.Equal(t, prefix+".*", msg.Pattern)
+	assert.Equal(t, "changed", msg.Payload)
+}
+
+func TestSubscription_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub, err := client.Subscribe(ctx, []string{uuid.NewString()})
+	require.NoError(t, err)
+
+	cancel()
+
+	select {
+	case <-sub.Done():
+	case <-time.After(time.Second * 5):
+		t.Fatal("subscription was not closed")
+	}
+
+	require.NoError(t, sub.Close())
+}
+
+func receiveMessage(t *testing.T, sub *redis.Subscription) redis.Message {
+	t.Helper()
+
+	select {
+	case msg, ok := <-sub.Messages():
+		require.True(t, ok, "messages is closed")
+		return msg
+	case <-time.After(time.Second * 5):
+		t.Fatal("no message received")
+	}
+
+	return redis.Message{}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tmeisel/glib/clients/redis"
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+func TestQueue_BatchQueue(t *testing.T) {
+	queue := client.Queue(uuid.NewString(), time.Second)
+	require.Implements(t, (*queuePkg.BatchQueue)(nil), queue)
+}
+
+func TestQueue_PushBatch(t *testing.T) {
+	var stats redis.BatchStats
+	queue := client.Queue(uuid.NewString(), time.Second).WithBatchObserver(stats.Observe)
+
+	values := make([]string, 0, redis.PushBatchChunkSize+10)
+	for i := 0; i < cap(values); i++ {
+		values = append(values, fmt.Sprintf("value-%d", i))
+	}
+
+	require.NoError(t, queue.PushBatch(values))
+
+	batches, pushed := stats.Pushed()
+	assert.Equal(t, int64(1), batches)
+	assert.Equal(t, int64(len(values)), pushed)
+
+	// order is preserved across chunks
+	for _, expected := range values[:3] {
+		val, err := queue.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+
+	require.NoError(t, queue.Empty())
+}
+
+func TestQueue_PopBatch(t *testing.T) {
+	var stats redis.BatchStats
+	queue := client.Queue(uuid.NewString(), time.Second).WithBatchObserver(stats.Observe)
+
+	// empty queue
+	_, err := queue.PopBatch(10)
+	require.ErrorIs(t, err, queuePkg.ErrEmpty)
+
+	require.NoError(t, queue.PushBatch([]string{"a", "b", "c"}))
+
+	values, err := queue.PopBatch(2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, values)
+
+	values, err = queue.PopBatch(10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, values)
+
+	batches, popped := stats.Popped()
+	assert.Equal(t, int64(2), batches)
+	assert.Equal(t, int64(3), popped)
+	assert.Equal(t, 1.5, stats.AveragePopSize())
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+func TestQueue_ContextQueue(t *testing.T) {
+	queue := client.Queue(uuid.NewString(), time.Second)
+	require.Implements(t, (*queuePkg.ContextQueue)(nil), queue)
+}
+
+func TestQueue_PopContext(t *testing.T) {
+	queue := client.Queue(uuid.NewString(), time.Second*2)
+	ctx := context.Background()
+
+	// empty queue
+	_, err := queue.PopContext(ctx)
+	require.ErrorIs(t, err, queuePkg.ErrEmpty)
+
+	require.NoError(t, queue.PushContext(ctx, "hello"))
+
+	val, err := queue.PopContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", val)
+}
+
+func TestQueue_PopContext_Cancel(t *testing.T) {
+	queue := client.Queue(uuid.NewString(), time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*100, cancel)
+
+	start := time.Now()
+	_, err := queue.PopContext(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second*3)
+
+	// cancelled contexts are refused
+	require.ErrorIs(t, queue.PushContext(ctx, "hello"), context.Canceled)
+	require.ErrorIs(t, queue.EmptyContext(ctx), context.Canceled)
+}
+
+func TestQueue_PopContext_Deadline(t *testing.T) {
+	queue := client.Queue(uuid.NewString(), time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := queue.PopContext(ctx)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second*3)
+}
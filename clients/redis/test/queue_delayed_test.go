@@ -0,0 +1,62 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+func TestQueue_PushDelayed(t *testing.T) {
+	queue := client.Queue(uuid.NewString(), time.Second)
+
+	require.NoError(t, queue.PushDelayed("later", time.Millisecond*500))
+	require.NoError(t, queue.PushDelayed("later", time.Millisecond*500))
+
+	delayed, err := queue.Delayed()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), delayed)
+
+	// not yet due
+	n, err := queue.Promote()
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	_, err = queue.Pop()
+	require.ErrorIs(t, err, queuePkg.ErrEmpty)
+
+	time.Sleep(time.Second)
+
+	n, err = queue.Promote()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	for i := 0; i < 2; i++ {
+		val, err := queue.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, "later", val)
+	}
+}
+
+func TestQueue_PushAt(t *testing.T) {
+	queue := client.Queue(uuid.NewString(), time.Second)
+
+	// in the past, pushed immediately
+	require.NoError(t, queue.PushAt("now", time.Now().Add(-time.Minute)))
+	require.NoError(t, queue.PushAt("tomorrow", time.Now().Add(time.Hour*24)))
+
+	val, err := queue.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "now", val)
+
+	require.NoError(t, queue.Empty())
+
+	delayed, err := queue.Delayed()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), delayed)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	goRedis "github.com/go-redis/redis"
 	"github.com/google/uuid"
 
 	"github.com/stretchr/testify/require"
@@ -17,6 +18,24 @@ func TestQueue(t *testing.T) {
 	require.Implements(t, (*queuePkg.Queue)(nil), queue)
 }
 
+func TestQueue_KeyLayout(t *testing.T) {
+	// elements pushed by previous versions, which stored them under "queue-<name>"
+	raw := goRedis.NewClient(&goRedis.Options{Addr: container.GetConfig().Addresses[0]})
+	defer func() { _ = raw.Close() }()
+
+	name := uuid.NewString()
+	require.NoError(t, raw.RPush("queue-"+name, "first", "second").Err())
+
+	val, err := client.Queue(name, time.Second).LPop()
+	require.NoError(t, err)
+	assert.Equal(t, "first", val)
+
+	msg, err := client.ReliableQueue(name, uuid.NewString(), time.Second, time.Second*5).Receive()
+	require.NoError(t, err)
+	assert.Equal(t, "second", msg.Value())
+	require.NoError(t, msg.Ack())
+}
+
 func TestLPop(t *testing.T) {
 	queue := client.Queue(uuid.NewString(), time.Second)
 
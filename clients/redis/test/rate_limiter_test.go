@@ -0,0 +1,69 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	limiter := client.RateLimiter(uuid.NewString(), 3, time.Minute)
+
+	for i := int64(0); i < 3; i++ {
+		res, err := limiter.Allow(ctx, "key")
+		require.NoError(t, err)
+
+		assert.True(t, res.Allowed)
+		assert.Equal(t, int64(3), res.Limit)
+		assert.Equal(t, 2-i, res.Remaining)
+		assert.Zero(t, res.RetryAfter)
+	}
+
+	res, err := limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+
+	assert.False(t, res.Allowed)
+	assert.Zero(t, res.Remaining)
+
+	// one request is allowed every 20 seconds
+	assert.InDelta(t, time.Second*20, res.RetryAfter, float64(time.Second))
+	assert.InDelta(t, time.Minute, res.Reset, float64(time.Second))
+
+	// keys are limited independently
+	res, err = limiter.Allow(ctx, "other")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	require.NoError(t, limiter.Reset("key"))
+
+	res, err = limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, int64(2), res.Remaining)
+}
+
+func TestRateLimiter_Refill(t *testing.T) {
+	ctx := context.Background()
+	limiter := client.RateLimiter(uuid.NewString(), 2, time.Millisecond*200)
+
+	for i := 0; i < 2; i++ {
+		res, err := limiter.Allow(ctx, "key")
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	}
+
+	res, err := limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+
+	time.Sleep(res.RetryAfter + time.Millisecond*10)
+
+	res, err = limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tmeisel/glib/clients/redis"
+	queuePkg "github.com/tmeisel/glib/queue"
+)
+
+func TestReliableQueue(t *testing.T) {
+	queue := client.ReliableQueue(uuid.NewString(), uuid.NewString(), time.Second, time.Second*5)
+	require.Implements(t, (*queuePkg.ReliableQueue)(nil), queue)
+}
+
+func TestReliableQueue_Ack(t *testing.T) {
+	queue := client.ReliableQueue(uuid.NewString(), uuid.NewString(), time.Second, time.Second*5)
+
+	// empty queue
+	_, err := queue.Receive()
+	require.ErrorIs(t, err, queuePkg.ErrEmpty)
+
+	require.NoError(t, queue.Push("hello"))
+
+	msg, err := queue.Receive()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", msg.Value())
+
+	inFlight, err := queue.InFlight()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), inFlight)
+
+	require.NoError(t, msg.Ack())
+	require.ErrorIs(t, msg.Ack(), redis.ErrNotInFlight)
+
+	inFlight, err = queue.InFlight()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), inFlight)
+
+	_, err = queue.Pop()
+	require.ErrorIs(t, err, queuePkg.ErrEmpty)
+}
+
+func TestReliableQueue_Nack(t *testing.T) {
+	queue := client.ReliableQueue(uuid.NewString(), uuid.NewString(), time.Second, time.Second*5)
+
+	require.NoError(t, queue.Push("hello"))
+
+	msg, err := queue.Receive()
+	require.NoError(t, err)
+	require.NoError(t, msg.Nack())
+
+	msg, err = queue.Receive()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", msg.Value())
+	require.NoError(t, msg.Ack())
+}
+
+func TestReliableQueue_Requeue(t *testing.T) {
+	name := uuid.NewString()
+
+	crashing := client.ReliableQueue(name, uuid.NewString(), time.Second, time.Second*2)
+	healthy := client.ReliableQueue(name, uuid.NewString(), time.Second, time.Second*30)
+
+	require.NoError(t, crashing.Push("first"))
+	require.NoError(t, crashing.Push("second"))
+
+	_, err := crashing.Receive()
+	require.NoError(t, err)
+
+	// lease of the consumer is still valid
+	n, err := healthy.Requeue()
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	time.Sleep(time.Second * 3)
+
+	n, err = healthy.Requeue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// the requeued message is delivered first
+	msg, err := healthy.Receive()
+	require.NoError(t, err)
+	assert.Equal(t, "first", msg.Value())
+	require.NoError(t, msg.Ack())
+
+	require.NoError(t, healthy.Empty())
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	queuePkg "github.com/tmeisel/glib/queue"
+	"github.com/tmeisel/glib/queue/queuetest"
+)
+
+func TestStream(t *testing.T) {
+	stream := client.Stream(uuid.NewString(), uuid.NewString(), uuid.NewString(), time.Second)
+	require.Implements(t, (*queuePkg.StreamQueue)(nil), stream)
+}
+
+func TestStream_Conformance(t *testing.T) {
+	queuetest.Run(t, func(t *testing.T) queuePkg.Queue {
+		return client.Stream(uuid.NewString(), uuid.NewString(), uuid.NewString(), time.Second)
+	})
+}
+
+func TestStream_FanOut(t *testing.T) {
+	name := uuid.NewString()
+
+	first := client.Stream(name, "first", uuid.NewString(), time.Second)
+	second := client.Stream(name, "second", uuid.NewString(), time.Second)
+
+	require.NoError(t, first.Push("hello"))
+
+	for _, stream := range []queuePkg.Queue{first, second} {
+		val, err := stream.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, "hello", val)
+	}
+
+	// replay the stream for the first group
+	require.NoError(t, first.SetGroupID("0"))
+
+	val, err := first.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", val)
+
+	require.NoError(t, first.Empty())
+}
+
+func TestStream_Nack(t *testing.T) {
+	stream := client.Stream(uuid.NewString(), uuid.NewString(), uuid.NewString(), time.Second)
+
+	require.NoError(t, stream.Push("hello"))
+
+	msg, err := stream.Receive()
+	require.NoError(t, err)
+	require.NoError(t, msg.Nack())
+
+	// the message stays pending, until it is claimed
+	pending, err := stream.Pending(10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, msg.(queuePkg.StreamMessage).ID(), pending[0].ID)
+
+	claimed, err := stream.Claim(0, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, "hello", claimed[0].Value())
+	require.NoError(t, claimed[0].Ack())
+
+	require.NoError(t, stream.Empty())
+}
+
+func TestStream_PendingAndClaim(t *testing.T) {
+	name := uuid.NewString()
+	group := uuid.NewString()
+
+	crashing := client.Stream(name, group, "crashing", time.Second)
+	healthy := client.Stream(name, group, "healthy", time.Second)
+
+	require.NoError(t, crashing.Push("hello"))
+
+	msg, err := crashing.Receive()
+	require.NoError(t, err)
+
+	pending, err := healthy.Pending(10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, msg.(queuePkg.StreamMessage).ID(), pending[0].ID)
+	assert.Equal(t, "crashing", pending[0].Consumer)
+	assert.Equal(t, int64(1), pending[0].Deliveries)
+
+	// not idle long enough
+	claimed, err := healthy.Claim(time.Hour, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	claimed, err = healthy.Claim(0, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, "hello", claimed[0].Value())
+
+	require.NoError(t, claimed[0].Ack())
+
+	pending, err = healthy.Pending(10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	require.NoError(t, healthy.Empty())
+}
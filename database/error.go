@@ -7,12 +7,12 @@ import (
 )
 
 var (
-	ErrInvalidLogin = errorPkg.New(errorPkg.CodeInvalidCredentials, errorPkg.CodeInvalidCredentials.String(), nil)
-	ErrNoRows       = errorPkg.New(errorPkg.CodeNotFound, errorPkg.CodeNotFound.String(), nil)
+	ErrInvalidLogin = errorPkg.New(errorPkg.CodeInvalidCredentials, errorPkg.CodeInvalidCredentials.HttpStatusText(), nil)
+	ErrNoRows       = errorPkg.New(errorPkg.CodeNotFound, errorPkg.CodeNotFound.HttpStatusText(), nil)
 )
 
 func NewError(err error) *errorPkg.Error {
-	return NewErrorMsg(err, errorPkg.CodeInternal.String())
+	return NewErrorMsg(err, errorPkg.CodeInternal.HttpStatusText())
 }
 
 func NewErrorMsg(err error, msg string) *errorPkg.Error {
@@ -51,11 +51,12 @@ func ProcessError(err error) *errPkg.Error {
 }
 
 func IsDuplicateKeyError(err error) bool {
-	if pkgErr, ok := err.(*errPkg.Error); ok {
-		return pkgErr.GetCode() == errPkg.CodeDuplicateKey
+	if errPkg.IsDuplicateKeyErr(err) {
+		return true
 	}
 
-	if pgconnErr, ok := err.(*pgconn.PgError); ok {
+	var pgconnErr *pgconn.PgError
+	if errors.As(err, &pgconnErr) {
 		return pgconnErr.Code == CodeDuplicateKey
 	}
 
@@ -1,8 +1,11 @@
 package error
 
 import (
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"sync"
 
 	"github.com/tmeisel/glib/utils/strutils"
 )
@@ -17,6 +20,8 @@ import (
 //
 //	40900 is a generic conflict
 //	40901 is a duplicate key error thrown by e.g. the database
+//
+// Applications can define their own codes using RegisterCode
 type Code int
 
 const (
@@ -31,9 +36,163 @@ const (
 	CodeGone               Code = 41000
 	CodeTooManyRequests    Code = 42900
 	CodeInternal           Code = 50000
+	CodeNotImplemented     Code = 50100
+	CodeUnavailable        Code = 50300
+	CodeTimeout            Code = 50400
+)
+
+var (
+	ErrInvalidCode   = New(CodeInternal, "code must consist of a 4xx or 5xx http status and 2 more digits", nil)
+	ErrCodeNameEmpty = New(CodeInternal, "code name must not be empty", nil)
+	ErrCodeExists    = New(CodeConflict, "code is already registered", nil)
+	ErrCodeNameTaken = New(CodeConflict, "code name is already registered", nil)
 )
 
+// CodeDefinition describes a Code
+type CodeDefinition struct {
+	Code Code `json:"code"`
+
+	// Name identifies the code, e.g. "version_conflict". It must be unique
+	Name string `json:"name"`
+
+	Description string `json:"description,omitempty"`
+
+	// Message is the default message of errors with the code. If it
+	// is empty, the http status text of the code is used
+	Message string `json:"message,omitempty"`
+}
+
+type codeRegistry struct {
+	mu     sync.RWMutex
+	byCode map[Code]CodeDefinition
+	byName map[string]CodeDefinition
+}
+
+var registry = &codeRegistry{
+	byCode: make(map[Code]CodeDefinition),
+	byName: make(map[string]CodeDefinition),
+}
+
+func init() {
+	for _, def := range []CodeDefinition{
+		{Code: CodeUser, Name: "bad_request", Description: "The request is invalid"},
+		{Code: CodeAuthRequired, Name: "auth_required", Description: "The request requires authentication"},
+		{Code: CodeInvalidCredentials, Name: "invalid_credentials", Description: "The given credentials are invalid"},
+		{Code: CodeForbidden, Name: "forbidden", Description: "The identity is not allowed to perform the request"},
+		{Code: CodeNotFound, Name: "not_found", Description: "The requested resource does not exist"},
+		{Code: CodeConflict, Name: "conflict", Description: "The request conflicts with the state of the resource"},
+		{Code: CodeDuplicateKey, Name: "duplicate_key", Description: "A resource with the same key exists already", Message: "duplicate key error"},
+		{Code: CodePreconditionFailed, Name: "precondition_failed", Description: "A precondition of the request is not met"},
+		{Code: CodeGone, Name: "gone", Description: "The requested resource is no longer available"},
+		{Code: CodeTooManyRequests, Name: "too_many_requests", Description: "The rate limit has been exceeded"},
+		{Code: CodeInternal, Name: "internal", Description: "An unexpected error occurred"},
+		{Code: CodeNotImplemented, Name: "not_implemented", Description: "The requested operation is not implemented"},
+		{Code: CodeUnavailable, Name: "unavailable", Description: "The service is temporarily unavailable"},
+		{Code: CodeTimeout, Name: "timeout", Description: "The operation did not complete in time"},
+	} {
+		MustRegisterCode(def)
+	}
+}
+
+// RegisterCode adds the definition of a custom code to the registry, so it can
+// be looked up by its number and name. Codes and names must be unique
+func RegisterCode(def CodeDefinition) error {
+	if !def.Code.valid() {
+		return ErrInvalidCode
+	}
+
+	if def.Name == "" {
+		return ErrCodeNameEmpty
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, exists := registry.byCode[def.Code]; exists {
+		return ErrCodeExists
+	}
+
+	if _, exists := registry.byName[def.Name]; exists {
+		return ErrCodeNameTaken
+	}
+
+	registry.byCode[def.Code] = def
+	registry.byName[def.Name] = def
+
+	return nil
+}
+
+// MustRegisterCode calls RegisterCode and panics, if it fails. It returns
+// the code, so it can be used to declare package level variables:
+//
+//	var CodeVersionConflict = errPkg.MustRegisterCode(errPkg.CodeDefinition{
+//		Code:    40902,
+//		Name:    "version_conflict",
+//		Message: "version conflict",
+//	})
+func MustRegisterCode(def CodeDefinition) Code {
+	if err := RegisterCode(def); err != nil {
+		panic(fmt.Sprintf("failed to register code %d: %v", def.Code, err))
+	}
+
+	return def.Code
+}
+
+// LookupCode returns the definition of code, if it is registered
+func LookupCode(code Code) (CodeDefinition, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	def, ok := registry.byCode[code]
+
+	return def, ok
+}
+
+// LookupCodeName returns the definition of the code
+// with the given name, if it is registered
+func LookupCodeName(name string) (CodeDefinition, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	def, ok := registry.byName[name]
+
+	return def, ok
+}
+
+// Codes returns the definitions of all registered codes, ordered by code
+func Codes() []CodeDefinition {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	defs := make([]CodeDefinition, 0, len(registry.byCode))
+	for _, def := range registry.byCode {
+		defs = append(defs, def)
+	}
+
+	slices.SortFunc(defs, func(a, b CodeDefinition) int {
+		return int(a.Code - b.Code)
+	})
+
+	return defs
+}
+
+// String returns the name of a registered code. For
+// other codes, the http status text is returned
 func (c Code) String() string {
+	if def, ok := LookupCode(c); ok {
+		return def.Name
+	}
+
+	return c.HttpStatusText()
+}
+
+// Message returns the default message of a registered code. For
+// other codes, the http status text is returned
+func (c Code) Message() string {
+	if def, ok := LookupCode(c); ok && def.Message != "" {
+		return def.Message
+	}
+
 	return c.HttpStatusText()
 }
 
@@ -54,3 +213,13 @@ func (c Code) HttpStatus() int {
 func (c Code) HttpStatusText() string {
 	return http.StatusText(c.HttpStatus())
 }
+
+// valid returns true, if c consists of 5 digits starting
+// with a known 4xx or 5xx http status
+func (c Code) valid() bool {
+	if c < 40000 || c > 59999 {
+		return false
+	}
+
+	return http.StatusText(c.HttpStatus()) != ""
+}
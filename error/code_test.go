@@ -0,0 +1,114 @@
+package error
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCode(t *testing.T) {
+	type testCase struct {
+		Definition CodeDefinition
+		Expected   error
+	}
+
+	for name, tc := range map[string]testCase{
+		"valid": {
+			Definition: CodeDefinition{Code: 40990, Name: "test_version_conflict", Message: "version conflict"},
+			Expected:   nil,
+		},
+		"code exists": {
+			Definition: CodeDefinition{Code: CodeDuplicateKey, Name: "test_duplicate"},
+			Expected:   ErrCodeExists,
+		},
+		"name taken": {
+			Definition: CodeDefinition{Code: 40991, Name: "duplicate_key"},
+			Expected:   ErrCodeNameTaken,
+		},
+		"empty name": {
+			Definition: CodeDefinition{Code: 40992},
+			Expected:   ErrCodeNameEmpty,
+		},
+		"too short": {
+			Definition: CodeDefinition{Code: 409, Name: "test_short"},
+			Expected:   ErrInvalidCode,
+		},
+		"no error status": {
+			Definition: CodeDefinition{Code: 20000, Name: "test_ok"},
+			Expected:   ErrInvalidCode,
+		},
+		"unknown status": {
+			Definition: CodeDefinition{Code: 49900, Name: "test_unknown"},
+			Expected:   ErrInvalidCode,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, RegisterCode(tc.Definition))
+		})
+	}
+}
+
+func TestMustRegisterCode(t *testing.T) {
+	code := MustRegisterCode(CodeDefinition{Code: 40993, Name: "test_must_register"})
+	assert.Equal(t, Code(40993), code)
+
+	assert.Panics(t, func() {
+		MustRegisterCode(CodeDefinition{Code: 40993, Name: "test_must_register_again"})
+	})
+}
+
+func TestLookupCode(t *testing.T) {
+	code := MustRegisterCode(CodeDefinition{
+		Code:        41201,
+		Name:        "test_version_mismatch",
+		Description: "The version of the resource does not match",
+		Message:     "version mismatch",
+	})
+
+	def, ok := LookupCode(code)
+	require.True(t, ok)
+	assert.Equal(t, "test_version_mismatch", def.Name)
+
+	byName, ok := LookupCodeName("test_version_mismatch")
+	require.True(t, ok)
+	assert.Equal(t, def, byName)
+
+	_, ok = LookupCode(40999)
+	assert.False(t, ok)
+
+	_, ok = LookupCodeName("unknown")
+	assert.False(t, ok)
+}
+
+func TestCodes(t *testing.T) {
+	codes := Codes()
+	require.NotEmpty(t, codes)
+
+	for i := 1; i < len(codes); i++ {
+		assert.Less(t, codes[i-1].Code, codes[i].Code)
+	}
+
+	assert.Contains(t, codes, CodeDefinition{
+		Code:        CodeNotFound,
+		Name:        "not_found",
+		Description: "The requested resource does not exist",
+	})
+}
+
+func TestCode_String(t *testing.T) {
+	assert.Equal(t, "duplicate_key", CodeDuplicateKey.String())
+	assert.Equal(t, "internal", CodeInternal.String())
+	assert.Equal(t, http.StatusText(http.StatusConflict), Code(40998).String())
+}
+
+func TestCode_Message(t *testing.T) {
+	assert.Equal(t, "duplicate key error", CodeDuplicateKey.Message())
+	assert.Equal(t, http.StatusText(http.StatusNotFound), CodeNotFound.Message())
+	assert.Equal(t, http.StatusText(http.StatusConflict), Code(40998).Message())
+
+	err := NewCode(CodeDuplicateKey, nil)
+	assert.Equal(t, CodeDuplicateKey, err.GetCode())
+	assert.Equal(t, "duplicate key error", err.Error())
+}
@@ -0,0 +1,90 @@
+package error
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Details hold structured information about an Error,
+// that API consumers can act upon
+type Details struct {
+	FieldViolations []FieldViolation
+	Metadata        map[string]string
+
+	// RetryAfter is the time after which the
+	// failed request may be retried
+	RetryAfter time.Duration
+
+	HelpLinks []HelpLink
+}
+
+// FieldViolation describes an invalid input field. Field
+// is the path of the field, e.g. "address.zip"
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// HelpLink points to documentation about an error
+type HelpLink struct {
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+// IsEmpty returns true, if no details are set
+func (d Details) IsEmpty() bool {
+	return len(d.FieldViolations) == 0 &&
+		len(d.Metadata) == 0 &&
+		d.RetryAfter == 0 &&
+		len(d.HelpLinks) == 0
+}
+
+// GetDetails returns the details of the error
+func (e Error) GetDetails() Details {
+	return e.details
+}
+
+// WithFieldViolation returns a copy of the error
+// with an additional field violation
+func (e Error) WithFieldViolation(field, description string) *Error {
+	e.details.FieldViolations = append(
+		slices.Clone(e.details.FieldViolations),
+		FieldViolation{Field: field, Description: description},
+	)
+
+	return &e
+}
+
+// WithMetadata returns a copy of the error, that
+// holds value for key in its metadata
+func (e Error) WithMetadata(key, value string) *Error {
+	metadata := maps.Clone(e.details.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+
+	metadata[key] = value
+	e.details.Metadata = metadata
+
+	return &e
+}
+
+// WithRetryAfter returns a copy of the error with
+// a hint, after which time a request may be retried
+func (e Error) WithRetryAfter(d time.Duration) *Error {
+	e.details.RetryAfter = d
+
+	return &e
+}
+
+// WithHelpLink returns a copy of the error
+// with an additional help link
+func (e Error) WithHelpLink(description, url string) *Error {
+	e.details.HelpLinks = append(
+		slices.Clone(e.details.HelpLinks),
+		HelpLink{Description: description, URL: url},
+	)
+
+	return &e
+}
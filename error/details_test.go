@@ -0,0 +1,54 @@
+package error
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_WithDetails(t *testing.T) {
+	base := NewUserMsg(nil, "invalid request")
+	assert.True(t, base.GetDetails().IsEmpty())
+
+	err := base.
+		WithFieldViolation("name", "must not be empty").
+		WithFieldViolation("address.zip", "must have 5 digits").
+		WithMetadata("resource", "user").
+		WithRetryAfter(time.Second*30).
+		WithHelpLink("API docs", "https://example.com/docs")
+
+	assert.Equal(t, Details{
+		FieldViolations: []FieldViolation{
+			{Field: "name", Description: "must not be empty"},
+			{Field: "address.zip", Description: "must have 5 digits"},
+		},
+		Metadata:   map[string]string{"resource": "user"},
+		RetryAfter: time.Second * 30,
+		HelpLinks:  []HelpLink{{Description: "API docs", URL: "https://example.com/docs"}},
+	}, err.GetDetails())
+
+	assert.Equal(t, base.GetCode(), err.GetCode())
+	assert.Equal(t, base.Error(), err.Error())
+	assert.True(t, errors.Is(err, base))
+
+	// the original error is not modified
+	assert.True(t, base.GetDetails().IsEmpty())
+}
+
+func TestError_WithDetailsCopies(t *testing.T) {
+	base := NewUserMsg(nil, "invalid request").
+		WithFieldViolation("a", "invalid").
+		WithMetadata("a", "1")
+
+	first := base.WithFieldViolation("b", "invalid").WithMetadata("b", "2")
+	second := base.WithFieldViolation("c", "invalid").WithMetadata("c", "3")
+
+	assert.Len(t, base.GetDetails().FieldViolations, 1)
+	assert.Len(t, base.GetDetails().Metadata, 1)
+
+	assert.Equal(t, "b", first.GetDetails().FieldViolations[1].Field)
+	assert.Equal(t, "c", second.GetDetails().FieldViolations[1].Field)
+	assert.NotContains(t, first.GetDetails().Metadata, "c")
+}
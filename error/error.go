@@ -1,46 +1,53 @@
 package error
 
-import (
-	"runtime"
-)
-
-const (
-	MaxStackDepth = 5
-)
-
 type Error struct {
-	code  Code
-	msg   string
-	prev  error
-	stack []uintptr
+	code    Code
+	msg     string
+	public  string
+	key     string
+	args    []any
+	prev    error
+	stack   []uintptr
+	details Details
+
+	// items are the errors of a MultiError summarized by the error
+	items []*Error
 }
 
 func New(code Code, msg string, prev error) *Error {
-	stack := make([]uintptr, MaxStackDepth)
-	length := runtime.Callers(2, stack[:])
+	return newError(code, msg, prev)
+}
 
+// newError must be called directly by the exported constructors,
+// so the captured stack starts at their caller
+func newError(code Code, msg string, prev error) *Error {
 	return &Error{
 		code:  code,
 		msg:   msg,
 		prev:  prev,
-		stack: stack[:length],
+		stack: callers(2),
 	}
 }
 
+// NewCode returns an error with the default message of code (see Code.Message)
+func NewCode(code Code, prev error) *Error {
+	return newError(code, code.Message(), prev)
+}
+
 func NewUser(prev error) *Error {
-	return NewUserMsg(prev, CodeUser.HttpStatusText())
+	return newError(CodeUser, CodeUser.HttpStatusText(), prev)
 }
 
 func NewUserMsg(prev error, msg string) *Error {
-	return New(CodeUser, msg, prev)
+	return newError(CodeUser, msg, prev)
 }
 
 func NewInternal(prev error) *Error {
-	return NewInternalMsg(prev, CodeInternal.HttpStatusText())
+	return newError(CodeInternal, CodeInternal.HttpStatusText(), prev)
 }
 
 func NewInternalMsg(prev error, msg string) *Error {
-	return New(CodeInternal, msg, prev)
+	return newError(CodeInternal, msg, prev)
 }
 
 func (e Error) GetCode() Code {
@@ -78,9 +85,87 @@ func (e Error) Is(err error) bool {
 	return false
 }
 
+// Is returns true, if err or any error wrapped by it is an *Error
+// with the given code. Errors joined with errors.Join are traversed
 func Is(err error, code Code) bool {
-	if pkgErr, ok := err.(*Error); ok {
+	return walk(err, func(pkgErr *Error) bool {
 		return pkgErr.code == code
+	})
+}
+
+// As returns the most relevant *Error in the chain of err (see CodeOf)
+func As(err error) (*Error, bool) {
+	pkgErr := find(err)
+
+	return pkgErr, pkgErr != nil
+}
+
+// CodeOf returns the code of the most relevant *Error in the chain of
+// err. That's the outermost one. For errors joined with errors.Join,
+// the one with the highest http status wins, e.g. an internal error over
+// a not found error. A *MultiError counts as an *Error with the code
+// of MultiError.GetCode. If there is no *Error, CodeInternal is returned
+func CodeOf(err error) Code {
+	if pkgErr := find(err); pkgErr != nil {
+		return pkgErr.code
+	}
+
+	return CodeInternal
+}
+
+// find returns the most relevant *Error in the chain of err
+func find(err error) *Error {
+	for err != nil {
+		switch typed := err.(type) {
+		case *Error:
+			return typed
+		case *MultiError:
+			return typed.summary()
+		case interface{ Unwrap() []error }:
+			var found *Error
+			for _, joined := range typed.Unwrap() {
+				if pkgErr := find(joined); pkgErr != nil && (found == nil || pkgErr.GetStatus() > found.GetStatus()) {
+					found = pkgErr
+				}
+			}
+
+			return found
+		case interface{ Unwrap() error }:
+			err = typed.Unwrap()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// walk calls fn for each *Error in the tree of err,
+// until it returns true. It returns true, if fn did
+func walk(err error, fn func(*Error) bool) bool {
+	for err != nil {
+		if pkgErr, ok := err.(*Error); ok && fn(pkgErr) {
+			return true
+		}
+
+		if multiErr, ok := err.(*MultiError); ok && fn(multiErr.summary()) {
+			return true
+		}
+
+		switch typed := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, joined := range typed.Unwrap() {
+				if walk(joined, fn) {
+					return true
+				}
+			}
+
+			return false
+		case interface{ Unwrap() error }:
+			err = typed.Unwrap()
+		default:
+			return false
+		}
 	}
 
 	return false
@@ -2,6 +2,7 @@ package error
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -42,6 +43,31 @@ func TestIs(t *testing.T) {
 			Code:     CodeUser,
 			Expected: false,
 		},
+		"wrapped": {
+			Error:    fmt.Errorf("wrapped: %w", New(CodeDuplicateKey, "msg", nil)),
+			Code:     CodeDuplicateKey,
+			Expected: true,
+		},
+		"previous error": {
+			Error:    NewInternal(New(CodeDuplicateKey, "msg", nil)),
+			Code:     CodeDuplicateKey,
+			Expected: true,
+		},
+		"joined": {
+			Error:    errors.Join(errors.New("some error"), fmt.Errorf("wrapped: %w", New(CodeDuplicateKey, "msg", nil))),
+			Code:     CodeDuplicateKey,
+			Expected: true,
+		},
+		"joined other": {
+			Error:    errors.Join(errors.New("some error"), New(CodeNotFound, "msg", nil)),
+			Code:     CodeDuplicateKey,
+			Expected: false,
+		},
+		"nil": {
+			Error:    nil,
+			Code:     CodeInternal,
+			Expected: false,
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			assert.Equal(t, tc.Expected, Is(tc.Error, tc.Code))
@@ -71,3 +97,120 @@ func TestIsDuplicateKeyErr(t *testing.T) {
 	}
 
 }
+
+func TestCodeOf(t *testing.T) {
+	type testCase struct {
+		Error    error
+		Expected Code
+	}
+
+	for name, tc := range map[string]testCase{
+		"other error": {
+			Error:    errors.New("some error"),
+			Expected: CodeInternal,
+		},
+		"error": {
+			Error:    New(CodeNotFound, "msg", nil),
+			Expected: CodeNotFound,
+		},
+		"wrapped": {
+			Error:    fmt.Errorf("wrapped: %w", New(CodeNotFound, "msg", nil)),
+			Expected: CodeNotFound,
+		},
+		"outermost": {
+			Error:    fmt.Errorf("wrapped: %w", NewUser(New(CodeNotFound, "msg", nil))),
+			Expected: CodeUser,
+		},
+		"joined": {
+			Error:    errors.Join(errors.New("some error"), New(CodeConflict, "msg", nil)),
+			Expected: CodeConflict,
+		},
+		"joined highest status": {
+			Error: errors.Join(
+				New(CodeNotFound, "msg", nil),
+				fmt.Errorf("wrapped: %w", NewInternal(nil)),
+				New(CodeConflict, "msg", nil),
+			),
+			Expected: CodeInternal,
+		},
+		"joined first of same status": {
+			Error:    errors.Join(New(CodeConflict, "msg", nil), New(CodeDuplicateKey, "msg", nil)),
+			Expected: CodeConflict,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, CodeOf(tc.Error))
+		})
+	}
+}
+
+func TestAs(t *testing.T) {
+	notFound := New(CodeNotFound, "msg", nil)
+
+	pkgErr, ok := As(fmt.Errorf("wrapped: %w", notFound))
+	assert.True(t, ok)
+	assert.Same(t, notFound, pkgErr)
+
+	pkgErr, ok = As(errors.New("some error"))
+	assert.False(t, ok)
+	assert.Nil(t, pkgErr)
+}
+
+func TestPublicMessage(t *testing.T) {
+	type testCase struct {
+		Error    error
+		Expected string
+	}
+
+	dbErr := errors.New(`duplicate key value violates unique constraint "users_email_key"`)
+
+	for name, tc := range map[string]testCase{
+		"other error": {
+			Error:    dbErr,
+			Expected: CodeInternal.Message(),
+		},
+		"user error": {
+			Error:    NewUserMsg(dbErr, "invalid email"),
+			Expected: "invalid email",
+		},
+		"internal error": {
+			Error:    NewInternalMsg(dbErr, "failed to insert user"),
+			Expected: CodeInternal.Message(),
+		},
+		"internal error with public message": {
+			Error:    NewInternalMsg(dbErr, "failed to insert user").WithPublicMessage("failed to create user"),
+			Expected: "failed to create user",
+		},
+		"user error with public message": {
+			Error:    New(CodeDuplicateKey, dbErr.Error(), dbErr).WithPublicMessage("email is taken"),
+			Expected: "email is taken",
+		},
+		"wrapped": {
+			Error:    fmt.Errorf("failed to handle request: %w", NewUserMsg(nil, "invalid email")),
+			Expected: "invalid email",
+		},
+		"multi": {
+			Error:    NewMulti(NewUserMsg(nil, "invalid email"), NewInternalMsg(dbErr, "failed to insert user")),
+			Expected: "invalid email; " + CodeInternal.Message(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, PublicMessage(tc.Error))
+		})
+	}
+}
+
+func TestInternalMessage(t *testing.T) {
+	dbErr := errors.New("connection refused")
+
+	assert.Equal(t, "", InternalMessage(nil))
+	assert.Equal(t, "connection refused", InternalMessage(dbErr))
+	assert.Equal(t,
+		"failed to handle request: failed to insert user: Internal Server Error: connection refused",
+		InternalMessage(fmt.Errorf("failed to handle request: %w", NewInternalMsg(NewInternal(dbErr), "failed to insert user"))),
+	)
+
+	err := NewInternalMsg(dbErr, "failed to insert user").WithPublicMessage("failed to create user")
+	assert.Equal(t, "failed to insert user", err.Error())
+	assert.Equal(t, "failed to insert user: connection refused", InternalMessage(err))
+}
@@ -0,0 +1,142 @@
+package error
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Catalog provides localized messages by language and message key
+type Catalog interface {
+	// Lookup returns the format of the message with the given key in the
+	// given language, e.g. "de" or "de-at". The format is passed to
+	// fmt.Sprintf along with the args of the message
+	Lookup(lang, key string) (string, bool)
+}
+
+// MapCatalog is a Catalog holding message formats by lower
+// case language tag and key:
+//
+//	errPkg.MapCatalog{
+//		"de": {"version_conflict": "Version %d ist veraltet"},
+//		"en": {"version_conflict": "version %d is outdated"},
+//	}
+type MapCatalog map[string]map[string]string
+
+func (c MapCatalog) Lookup(lang, key string) (string, bool) {
+	format, ok := c[lang][key]
+
+	return format, ok
+}
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   []Catalog
+)
+
+// RegisterCatalog adds a catalog used to localize messages. Catalogs
+// are consulted in the order they have been registered
+func RegisterCatalog(catalog Catalog) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	catalogs = append(catalogs, catalog)
+}
+
+// WithMessageKey returns a copy of the error with a message key, that
+// is used to look up a localized message in the registered catalogs.
+// args are the arguments of the message format
+func (e Error) WithMessageKey(key string, args ...any) *Error {
+	e.key = key
+	e.args = slices.Clone(args)
+
+	return &e
+}
+
+// MessageKey returns the message key and its arguments
+func (e Error) MessageKey() (string, []any) {
+	return e.key, e.args
+}
+
+// LocalizedMessage returns the public message of the error (see PublicMessage)
+// in the first of the given languages, that a registered catalog provides it
+// for. Errors without a message key, whose public message is the default of
+// their code, are localized using the code (see Code.LocalizedMessage). If
+// no localized message is found, the public message is returned
+func (e Error) LocalizedMessage(langs ...string) string {
+	if e.items != nil {
+		msgs := make([]string, 0, len(e.items))
+		for _, item := range e.items {
+			msgs = append(msgs, item.LocalizedMessage(langs...))
+		}
+
+		return strings.Join(msgs, "; ")
+	}
+
+	if e.key == "" {
+		if e.public == "" && e.PublicMessage() == e.code.Message() {
+			return e.code.LocalizedMessage(langs...)
+		}
+
+		return e.PublicMessage()
+	}
+
+	if msg, ok := lookupMessage(langs, e.key, e.args); ok {
+		return msg
+	}
+
+	return e.PublicMessage()
+}
+
+// LocalizedMessage returns the default message of the code in the first of
+// the given languages, that a registered catalog provides it for. The name
+// of the code is used as the message key (see CodeDefinition). If no
+// localized message is found, Message is returned
+func (c Code) LocalizedMessage(langs ...string) string {
+	if def, ok := LookupCode(c); ok {
+		if msg, ok := lookupMessage(langs, def.Name, nil); ok {
+			return msg
+		}
+	}
+
+	return c.Message()
+}
+
+// LocalizedMessage returns the localized message of the most relevant
+// *Error in the chain of err (see CodeOf and Error.LocalizedMessage). For
+// other errors, the localized default message of CodeInternal is returned
+func LocalizedMessage(err error, langs ...string) string {
+	if pkgErr := find(err); pkgErr != nil {
+		return pkgErr.LocalizedMessage(langs...)
+	}
+
+	return CodeInternal.LocalizedMessage(langs...)
+}
+
+// lookupMessage returns the message for key in the first of langs found
+// in the catalogs. Regional languages like "de-at" fall back to their
+// base language "de"
+func lookupMessage(langs []string, key string, args []any) (string, bool) {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	for _, lang := range langs {
+		lang = strings.ToLower(lang)
+
+		candidates := []string{lang}
+		if base, _, regional := strings.Cut(lang, "-"); regional {
+			candidates = append(candidates, base)
+		}
+
+		for _, candidate := range candidates {
+			for _, catalog := range catalogs {
+				if format, ok := catalog.Lookup(candidate, key); ok {
+					return fmt.Sprintf(format, args...), true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
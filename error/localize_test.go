@@ -0,0 +1,126 @@
+package error
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	RegisterCatalog(MapCatalog{
+		"de": {
+			"test_version_conflict": "Version %d ist veraltet",
+			"not_found":             "Nicht gefunden",
+			"internal":              "Interner Fehler",
+		},
+		"de-ch": {
+			"not_found": "Nöd gfunde",
+		},
+		"en": {
+			"test_version_conflict": "version %d is outdated",
+		},
+	})
+}
+
+func TestError_LocalizedMessage(t *testing.T) {
+	type testCase struct {
+		Error    error
+		Langs    []string
+		Expected string
+	}
+
+	conflict := New(CodeConflict, "version conflict", nil).WithMessageKey("test_version_conflict", 3)
+
+	for name, tc := range map[string]testCase{
+		"key": {
+			Error:    conflict,
+			Langs:    []string{"de"},
+			Expected: "Version 3 ist veraltet",
+		},
+		"key in preferred language": {
+			Error:    conflict,
+			Langs:    []string{"fr", "en", "de"},
+			Expected: "version 3 is outdated",
+		},
+		"key in base language": {
+			Error:    conflict,
+			Langs:    []string{"DE-AT"},
+			Expected: "Version 3 ist veraltet",
+		},
+		"key without language": {
+			Error:    conflict,
+			Expected: "version conflict",
+		},
+		"unknown key": {
+			Error:    New(CodeConflict, "version conflict", nil).WithMessageKey("unknown"),
+			Langs:    []string{"de"},
+			Expected: "version conflict",
+		},
+		"unknown language": {
+			Error:    conflict,
+			Langs:    []string{"fr"},
+			Expected: "version conflict",
+		},
+		"code default": {
+			Error:    NewCode(CodeNotFound, nil),
+			Langs:    []string{"de"},
+			Expected: "Nicht gefunden",
+		},
+		"code default in regional language": {
+			Error:    NewCode(CodeNotFound, nil),
+			Langs:    []string{"de-CH"},
+			Expected: "Nöd gfunde",
+		},
+		"custom message": {
+			Error:    New(CodeNotFound, "user not found", nil),
+			Langs:    []string{"de"},
+			Expected: "user not found",
+		},
+		"internal error": {
+			Error:    NewInternalMsg(errors.New("connection refused"), "failed to insert user"),
+			Langs:    []string{"de"},
+			Expected: "Interner Fehler",
+		},
+		"other error": {
+			Error:    errors.New("connection refused"),
+			Langs:    []string{"de"},
+			Expected: "Interner Fehler",
+		},
+		"wrapped": {
+			Error:    fmt.Errorf("wrapped: %w", conflict),
+			Langs:    []string{"de"},
+			Expected: "Version 3 ist veraltet",
+		},
+		"multi": {
+			Error:    NewMulti(conflict, NewCode(CodeNotFound, nil)),
+			Langs:    []string{"de"},
+			Expected: "Version 3 ist veraltet; Nicht gefunden",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, LocalizedMessage(tc.Error, tc.Langs...))
+		})
+	}
+}
+
+func TestCode_LocalizedMessage(t *testing.T) {
+	assert.Equal(t, "Nicht gefunden", CodeNotFound.LocalizedMessage("de"))
+	assert.Equal(t, http.StatusText(http.StatusNotFound), CodeNotFound.LocalizedMessage("fr"))
+	assert.Equal(t, http.StatusText(http.StatusConflict), Code(40998).LocalizedMessage("de"))
+}
+
+func TestError_WithMessageKey(t *testing.T) {
+	base := New(CodeConflict, "version conflict", nil)
+	err := base.WithMessageKey("test_version_conflict", 3)
+
+	key, args := err.MessageKey()
+	assert.Equal(t, "test_version_conflict", key)
+	assert.Equal(t, []any{3}, args)
+	assert.Equal(t, "version conflict", err.Error())
+
+	key, _ = base.MessageKey()
+	assert.Empty(t, key)
+}
@@ -0,0 +1,60 @@
+package error
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithPublicMessage returns a copy of the error with a message, that
+// is safe to be shown to clients, while Error returns the original one
+func (e Error) WithPublicMessage(msg string) *Error {
+	e.public = msg
+
+	return &e
+}
+
+// PublicMessage returns the message, that is safe to be shown to clients.
+// That's the message set using WithPublicMessage. If there is none, the
+// message of 4xx errors is returned. 5xx errors are described by the
+// default message of their code (see Code.Message) instead, as their
+// message may contain internal details
+func (e Error) PublicMessage() string {
+	if e.public != "" {
+		return e.public
+	}
+
+	if e.GetStatus() < 500 {
+		return e.msg
+	}
+
+	return e.code.Message()
+}
+
+// PublicMessage returns the public message of the most relevant *Error
+// in the chain of err (see CodeOf). Messages of other errors are never
+// exposed. Instead, the default message of CodeInternal is returned
+func PublicMessage(err error) string {
+	if pkgErr := find(err); pkgErr != nil {
+		return pkgErr.PublicMessage()
+	}
+
+	return CodeInternal.Message()
+}
+
+// InternalMessage returns the message of err followed by the messages
+// of the errors wrapped by *Errors in its chain, which Error omits. It
+// is meant for logs and must not be exposed to clients
+func InternalMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	msg := err.Error()
+	for next := err; next != nil; next = errors.Unwrap(next) {
+		if pkgErr, ok := next.(*Error); ok && pkgErr.prev != nil {
+			return fmt.Sprintf("%s: %s", msg, InternalMessage(pkgErr.prev))
+		}
+	}
+
+	return msg
+}
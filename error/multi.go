@@ -0,0 +1,118 @@
+package error
+
+import (
+	"slices"
+	"strings"
+)
+
+// MultiError aggregates multiple errors, e.g. the failures of
+// the items of a batch request. Its code is derived from the
+// codes of its errors (see MultiError.GetCode)
+type MultiError struct {
+	errs []*Error
+}
+
+// NewMulti returns a MultiError holding errs. Nil errors are skipped
+func NewMulti(errs ...*Error) *MultiError {
+	return MultiError{}.Append(errs...)
+}
+
+// Append returns a copy of the MultiError with errs added.
+// Nil errors are skipped
+func (m MultiError) Append(errs ...*Error) *MultiError {
+	m.errs = slices.Clone(m.errs)
+	for _, err := range errs {
+		if err != nil {
+			m.errs = append(m.errs, err)
+		}
+	}
+
+	return &m
+}
+
+// Errors returns the errors of the MultiError
+func (m MultiError) Errors() []*Error {
+	return m.errs
+}
+
+// Len returns the number of errors
+func (m MultiError) Len() int {
+	return len(m.errs)
+}
+
+// ErrorOrNil returns the MultiError, if it holds any
+// errors. Otherwise, it returns nil
+func (m MultiError) ErrorOrNil() error {
+	if len(m.errs) == 0 {
+		return nil
+	}
+
+	return &m
+}
+
+// GetCode returns the code of the errors, if they all share
+// one. If they share an http status, the generic code of that
+// status is returned, e.g. 40400 for 40401 and 40402. Otherwise,
+// it's CodeInternal, if any of the errors is a 5xx error, and
+// CodeUser, if all of them are 4xx errors
+func (m MultiError) GetCode() Code {
+	if len(m.errs) == 0 {
+		return CodeInternal
+	}
+
+	code := m.errs[0].code
+	status := code.HttpStatus()
+	sameCode, sameStatus, internal := true, true, false
+
+	for _, err := range m.errs {
+		sameCode = sameCode && err.code == code
+		sameStatus = sameStatus && err.GetStatus() == status
+		internal = internal || err.GetStatus() >= 500
+	}
+
+	switch {
+	case sameCode:
+		return code
+	case sameStatus:
+		return Code(status * 100)
+	case internal:
+		return CodeInternal
+	default:
+		return CodeUser
+	}
+}
+
+// GetStatus returns the http status of the code of the MultiError
+func (m MultiError) GetStatus() int {
+	return m.GetCode().HttpStatus()
+}
+
+// Error joins the messages of the errors using "; "
+func (m MultiError) Error() string {
+	msgs := make([]string, 0, len(m.errs))
+	for _, err := range m.errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors, so errors.Is and errors.As can match them
+func (m MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(m.errs))
+	for _, err := range m.errs {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// summary returns an *Error with the code and messages of the MultiError
+func (m MultiError) summary() *Error {
+	public := make([]string, 0, len(m.errs))
+	for _, err := range m.errs {
+		public = append(public, err.PublicMessage())
+	}
+
+	return &Error{code: m.GetCode(), msg: m.Error(), public: strings.Join(public, "; "), items: m.errs}
+}
@@ -0,0 +1,74 @@
+package error
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiError_GetCode(t *testing.T) {
+	type testCase struct {
+		Errors   []*Error
+		Expected Code
+	}
+
+	for name, tc := range map[string]testCase{
+		"empty": {
+			Errors:   nil,
+			Expected: CodeInternal,
+		},
+		"same code": {
+			Errors:   []*Error{New(CodeNotFound, "a", nil), New(CodeNotFound, "b", nil)},
+			Expected: CodeNotFound,
+		},
+		"same status": {
+			Errors:   []*Error{New(CodeConflict, "a", nil), New(CodeDuplicateKey, "b", nil)},
+			Expected: CodeConflict,
+		},
+		"all 4xx": {
+			Errors:   []*Error{New(CodeNotFound, "a", nil), New(CodeDuplicateKey, "b", nil)},
+			Expected: CodeUser,
+		},
+		"any 5xx": {
+			Errors:   []*Error{New(CodeNotFound, "a", nil), NewInternal(nil), New(CodeDuplicateKey, "b", nil)},
+			Expected: CodeInternal,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, NewMulti(tc.Errors...).GetCode())
+		})
+	}
+}
+
+func TestMultiError(t *testing.T) {
+	notFound := New(CodeNotFound, "user 1 not found", nil)
+	duplicate := New(CodeDuplicateKey, "user 2 exists", nil)
+
+	empty := NewMulti()
+	assert.Nil(t, empty.ErrorOrNil())
+
+	multi := empty.Append(notFound, nil).Append(duplicate)
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, 2, multi.Len())
+	assert.Equal(t, []*Error{notFound, duplicate}, multi.Errors())
+	assert.Equal(t, "user 1 not found; user 2 exists", multi.Error())
+	assert.Equal(t, 400, multi.GetStatus())
+
+	err := fmt.Errorf("batch failed: %w", multi.ErrorOrNil())
+
+	assert.True(t, errors.Is(err, duplicate))
+	assert.True(t, Is(err, CodeNotFound))
+	assert.True(t, Is(err, CodeUser))
+	assert.False(t, Is(err, CodeInternal))
+	assert.Equal(t, CodeUser, CodeOf(err))
+
+	var pkgErr *Error
+	assert.True(t, errors.As(err, &pkgErr))
+	assert.Same(t, notFound, pkgErr)
+
+	var multiErr *MultiError
+	assert.True(t, errors.As(err, &multiErr))
+	assert.Equal(t, 2, multiErr.Len())
+}
@@ -0,0 +1,124 @@
+package error
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// DefaultStackDepth is the number of frames New captures,
+	// unless it is changed using SetStackDepth
+	DefaultStackDepth = 32
+
+	// Deprecated: use DefaultStackDepth
+	MaxStackDepth = DefaultStackDepth
+)
+
+var stackDepth = newStackDepth(DefaultStackDepth)
+
+func newStackDepth(depth int32) *atomic.Int32 {
+	var d atomic.Int32
+	d.Store(depth)
+
+	return &d
+}
+
+// SetStackDepth sets the number of frames captured by errors
+// created afterwards. A depth of 0 disables capturing stacks
+func SetStackDepth(depth int) {
+	stackDepth.Store(int32(max(depth, 0)))
+}
+
+// Frame is a resolved frame of a stack trace
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String formats the frame as "function file:line"
+func (f Frame) String() string {
+	return fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line)
+}
+
+// StackTrace is a list of frames with the most recent call first
+type StackTrace []Frame
+
+// String formats the stack trace with one frame per line, where the
+// function and the location are separated by a new line and a tab
+func (s StackTrace) String() string {
+	var sb strings.Builder
+	for i, frame := range s {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+
+		_, _ = fmt.Fprintf(&sb, "%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+	}
+
+	return sb.String()
+}
+
+// callers returns the program counters of the calling stack. skip
+// is the number of frames to skip, excluding callers itself
+func callers(skip int) []uintptr {
+	depth := stackDepth.Load()
+	if depth == 0 {
+		return nil
+	}
+
+	stack := make([]uintptr, depth)
+	length := runtime.Callers(skip+2, stack)
+
+	return stack[:length]
+}
+
+// StackTrace resolves the stack captured when the error has been created
+func (e Error) StackTrace() StackTrace {
+	if len(e.stack) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(e.stack)
+	trace := make(StackTrace, 0, len(e.stack))
+
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+
+		if !more {
+			return trace
+		}
+	}
+}
+
+// Format implements fmt.Formatter. %s and %v print the message, %q prints
+// it quoted. %+v prints the message and the stack trace of the error
+// followed by its previous errors
+func (e Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, e.msg)
+
+			if trace := e.StackTrace(); len(trace) > 0 {
+				_, _ = fmt.Fprintf(s, "\n%s", trace)
+			}
+
+			if e.prev != nil {
+				_, _ = fmt.Fprintf(s, "\ncaused by: %+v", e.prev)
+			}
+
+			return
+		}
+
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, e.msg)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.msg)
+	}
+}
@@ -0,0 +1,57 @@
+package error
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_StackTrace(t *testing.T) {
+	for name, newFn := range map[string]func() *Error{
+		"New":            func() *Error { return New(CodeConflict, "conflict", nil) },
+		"NewCode":        func() *Error { return NewCode(CodeConflict, nil) },
+		"NewUser":        func() *Error { return NewUser(nil) },
+		"NewUserMsg":     func() *Error { return NewUserMsg(nil, "invalid") },
+		"NewInternal":    func() *Error { return NewInternal(nil) },
+		"NewInternalMsg": func() *Error { return NewInternalMsg(nil, "failed") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			trace := newFn().StackTrace()
+			require.NotEmpty(t, trace)
+
+			// the stack starts at the caller of the constructor
+			assert.Contains(t, trace[0].Function, "TestError_StackTrace")
+			assert.True(t, strings.HasSuffix(trace[0].File, "stack_test.go"))
+			assert.Positive(t, trace[0].Line)
+		})
+	}
+}
+
+func TestSetStackDepth(t *testing.T) {
+	defer SetStackDepth(DefaultStackDepth)
+
+	SetStackDepth(1)
+	assert.Len(t, New(CodeInternal, "failed", nil).StackTrace(), 1)
+
+	SetStackDepth(0)
+	assert.Empty(t, New(CodeInternal, "failed", nil).StackTrace())
+	assert.Equal(t, "failed", fmt.Sprintf("%+v", New(CodeInternal, "failed", nil)))
+}
+
+func TestError_Format(t *testing.T) {
+	err := NewInternalMsg(NewUserMsg(errors.New("root cause"), "invalid"), "failed")
+
+	assert.Equal(t, "failed", fmt.Sprintf("%s", err))
+	assert.Equal(t, "failed", fmt.Sprintf("%v", err))
+	assert.Equal(t, `"failed"`, fmt.Sprintf("%q", err))
+
+	verbose := fmt.Sprintf("%+v", err)
+	assert.True(t, strings.HasPrefix(verbose, "failed\n"))
+	assert.Contains(t, verbose, "TestError_Format\n\t")
+	assert.Contains(t, verbose, "\ncaused by: invalid\n")
+	assert.True(t, strings.HasSuffix(verbose, "\ncaused by: root cause"))
+}
@@ -1,9 +1,12 @@
 package fields
 
 import (
+	"errors"
+	"runtime"
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type Field = zap.Field
@@ -40,6 +43,65 @@ func Any(key string, val interface{}) Field {
 	return zap.Any(key, val)
 }
 
+// Error adds e under the key "error". If e is or wraps an error with
+// a stack trace, like errPkg.Error of github.com/tmeisel/glib/error,
+// its frames are added under the key "errorStack"
 func Error(e error) Field {
-	return zap.Error(e)
+	var stacker interface{ GetStack() []uintptr }
+	if !errors.As(e, &stacker) {
+		return zap.Error(e)
+	}
+
+	trace := resolveStack(stacker.GetStack())
+	if len(trace) == 0 {
+		return zap.Error(e)
+	}
+
+	return Field{Key: "error", Type: zapcore.InlineMarshalerType, Interface: stackError{err: e, trace: trace}}
+}
+
+// resolveStack returns the frames of the program counters of stack
+func resolveStack(stack []uintptr) []runtime.Frame {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(stack)
+	trace := make([]runtime.Frame, 0, len(stack))
+
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, frame)
+
+		if !more {
+			return trace
+		}
+	}
+}
+
+// stackError encodes an error and a stack trace inline
+type stackError struct {
+	err   error
+	trace []runtime.Frame
+}
+
+func (s stackError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("error", s.err.Error())
+
+	return enc.AddArray("errorStack", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		for _, frame := range s.trace {
+			err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+				enc.AddString("function", frame.Function)
+				enc.AddString("file", frame.File)
+				enc.AddInt("line", frame.Line)
+
+				return nil
+			}))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
 }
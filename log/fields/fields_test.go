@@ -0,0 +1,68 @@
+package fields
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// stackErr captures the stack like errPkg.Error
+type stackErr struct {
+	msg   string
+	stack []uintptr
+}
+
+func newStackErr(msg string) *stackErr {
+	stack := make([]uintptr, 32)
+	length := runtime.Callers(2, stack)
+
+	return &stackErr{msg: msg, stack: stack[:length]}
+}
+
+func (e *stackErr) Error() string {
+	return e.msg
+}
+
+func (e *stackErr) GetStack() []uintptr {
+	return e.stack
+}
+
+func TestError(t *testing.T) {
+	t.Run("plain error", func(t *testing.T) {
+		enc := zapcore.NewMapObjectEncoder()
+		Error(errors.New("failed")).AddTo(enc)
+
+		assert.Equal(t, map[string]interface{}{"error": "failed"}, enc.Fields)
+	})
+
+	t.Run("without stack", func(t *testing.T) {
+		enc := zapcore.NewMapObjectEncoder()
+		Error(&stackErr{msg: "failed"}).AddTo(enc)
+
+		assert.Equal(t, map[string]interface{}{"error": "failed"}, enc.Fields)
+	})
+
+	t.Run("wrapped error with stack", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", newStackErr("failed"))
+
+		enc := zapcore.NewMapObjectEncoder()
+		Error(err).AddTo(enc)
+
+		assert.Equal(t, "wrapped: failed", enc.Fields["error"])
+
+		stack, ok := enc.Fields["errorStack"].([]interface{})
+		require.True(t, ok)
+		require.NotEmpty(t, stack)
+
+		frame, ok := stack[0].(map[string]interface{})
+		require.True(t, ok)
+		assert.Contains(t, frame["function"], "TestError")
+		assert.Contains(t, frame["file"], "fields_test.go")
+		assert.Positive(t, frame["line"])
+	})
+}
@@ -7,11 +7,14 @@ require (
 	github.com/gorilla/mux v1.8.1
 	github.com/mvrilo/go-redoc v0.1.5
 	github.com/stretchr/testify v1.10.0
-	github.com/tmeisel/glib/ctx v0.0.5
+	github.com/tmeisel/glib/ctx v0.0.7
 	github.com/tmeisel/glib/database v0.0.3
-	github.com/tmeisel/glib/error v0.0.6
+	github.com/tmeisel/glib/error v0.0.10
 	github.com/tmeisel/glib/log v0.0.6
 	github.com/tmeisel/glib/utils v0.0.2
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142
+	google.golang.org/grpc v1.67.1
+	google.golang.org/protobuf v1.34.2
 )
 
 require (
@@ -21,5 +24,8 @@ require (
 	github.com/rs/cors v1.11.1 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
 	go.uber.org/zap v1.27.0 // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	ctxPkg "github.com/tmeisel/glib/ctx"
+	errPkg "github.com/tmeisel/glib/error"
+	"github.com/tmeisel/glib/log/fields"
+)
+
+// UnaryServerInterceptor converts errors returned by handlers to
+// grpc statuses using ToStatus. Internal errors are logged using
+// the logger of the request context
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		res, err := handler(ctx, req)
+		if err != nil {
+			return res, toStatusErr(ctx, err)
+		}
+
+		return res, nil
+	}
+}
+
+// StreamServerInterceptor converts errors returned by handlers to
+// grpc statuses using ToStatus. Internal errors are logged using
+// the logger of the stream context
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return toStatusErr(ss.Context(), err)
+		}
+
+		return nil
+	}
+}
+
+func toStatusErr(ctx context.Context, err error) error {
+	code := errPkg.CodeOf(err)
+
+	// ToStatus passes grpc statuses through, so their code is logged
+	if _, isPkgErr := errPkg.As(err); !isPkgErr {
+		if st, ok := status.FromError(err); ok {
+			code = FromGRPCCode(st.Code())
+		}
+	}
+
+	if logger := ctxPkg.GetLogger(ctx); logger != nil && code.HttpStatus() >= 500 {
+		logger.Errorf(ctx, "[%d] %s", code, errPkg.InternalMessage(err), fields.Error(err))
+	}
+
+	return ToStatus(err).Err()
+}
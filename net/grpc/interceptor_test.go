@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ctxPkg "github.com/tmeisel/glib/ctx"
+	errPkg "github.com/tmeisel/glib/error"
+	logPkg "github.com/tmeisel/glib/log"
+	"github.com/tmeisel/glib/log/testlogger"
+)
+
+type serverStream struct {
+	grpc.ServerStream
+}
+
+func (s serverStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+
+	res, err := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return "res", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "res", res)
+
+	_, err = interceptor(context.Background(), "req", &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return nil, errPkg.New(errPkg.CodeNotFound, "user not found", nil)
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor()
+
+	err := interceptor(nil, serverStream{}, &grpc.StreamServerInfo{}, func(srv any, stream grpc.ServerStream) error {
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = interceptor(nil, serverStream{}, &grpc.StreamServerInfo{}, func(srv any, stream grpc.ServerStream) error {
+		return errPkg.NewUserMsg(nil, "invalid request")
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestToStatusErr_Logging(t *testing.T) {
+	type testCase struct {
+		err     error
+		expCode codes.Code
+		expLogs int
+	}
+
+	for name, tc := range map[string]testCase{
+		"internal error": {
+			err:     errPkg.NewInternal(fmt.Errorf("connection refused")),
+			expCode: codes.Internal,
+			expLogs: 1,
+		},
+		"user error": {
+			err:     errPkg.NewUserMsg(nil, "invalid request"),
+			expCode: codes.InvalidArgument,
+		},
+		"wrapped status": {
+			err:     fmt.Errorf("failed to call users: %w", status.Error(codes.NotFound, "user not found")),
+			expCode: codes.NotFound,
+		},
+		"wrapped internal status": {
+			err:     fmt.Errorf("failed to call users: %w", status.Error(codes.Unavailable, "unavailable")),
+			expCode: codes.Unavailable,
+			expLogs: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			logger := testlogger.New(logPkg.LevelDebug)
+
+			err := toStatusErr(ctxPkg.WithLogger(context.Background(), logger), tc.err)
+			assert.Equal(t, tc.expCode, status.Code(err))
+			assert.Len(t, logger.GetEntries(), tc.expLogs)
+		})
+	}
+}
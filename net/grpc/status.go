@@ -0,0 +1,230 @@
+package grpc
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+// ErrorInfoDomain is the domain of the errdetails.ErrorInfo added by ToStatus.
+// FromStatus only derives the code from an ErrorInfo of this domain
+const ErrorInfoDomain = "github.com/tmeisel/glib"
+
+// codeByCode maps codes to grpc codes, that cannot
+// be derived from the http status of the code
+var codeByCode = map[errPkg.Code]codes.Code{
+	errPkg.CodeDuplicateKey: codes.AlreadyExists,
+}
+
+var codeByStatus = map[int]codes.Code{
+	400: codes.InvalidArgument,
+	401: codes.Unauthenticated,
+	403: codes.PermissionDenied,
+	404: codes.NotFound,
+	409: codes.Aborted,
+	410: codes.NotFound,
+	412: codes.FailedPrecondition,
+	429: codes.ResourceExhausted,
+	500: codes.Internal,
+	501: codes.Unimplemented,
+	503: codes.Unavailable,
+	504: codes.DeadlineExceeded,
+}
+
+var codeByGRPCCode = map[codes.Code]errPkg.Code{
+	codes.InvalidArgument:    errPkg.CodeUser,
+	codes.OutOfRange:         errPkg.CodeUser,
+	codes.Unauthenticated:    errPkg.CodeAuthRequired,
+	codes.PermissionDenied:   errPkg.CodeForbidden,
+	codes.NotFound:           errPkg.CodeNotFound,
+	codes.AlreadyExists:      errPkg.CodeDuplicateKey,
+	codes.Aborted:            errPkg.CodeConflict,
+	codes.FailedPrecondition: errPkg.CodePreconditionFailed,
+	codes.ResourceExhausted:  errPkg.CodeTooManyRequests,
+	codes.Unimplemented:      errPkg.CodeNotImplemented,
+	codes.Unavailable:        errPkg.CodeUnavailable,
+	codes.DeadlineExceeded:   errPkg.CodeTimeout,
+}
+
+// ToGRPCCode returns the grpc code corresponding to code. Codes
+// are mapped by their http status, if there is no specific mapping.
+// Unknown 4xx codes become codes.InvalidArgument, others codes.Internal
+func ToGRPCCode(code errPkg.Code) codes.Code {
+	if grpcCode, ok := codeByCode[code]; ok {
+		return grpcCode
+	}
+
+	httpStatus := code.HttpStatus()
+	if grpcCode, ok := codeByStatus[httpStatus]; ok {
+		return grpcCode
+	}
+
+	if httpStatus >= 400 && httpStatus < 500 {
+		return codes.InvalidArgument
+	}
+
+	return codes.Internal
+}
+
+// FromGRPCCode returns the code corresponding to grpcCode. grpc
+// codes without a corresponding code become errPkg.CodeInternal
+func FromGRPCCode(grpcCode codes.Code) errPkg.Code {
+	if code, ok := codeByGRPCCode[grpcCode]; ok {
+		return code
+	}
+
+	return errPkg.CodeInternal
+}
+
+// ToStatus converts err to a grpc status. If err has an *errPkg.Error in its
+// chain, its code is mapped using ToGRPCCode and its details are attached as
+// errdetails.ErrorInfo, errdetails.BadRequest, errdetails.RetryInfo and
+// errdetails.Help. Only the public message of err is exposed (see
+// errPkg.PublicMessage). Errors without an *errPkg.Error, that are or wrap
+// grpc statuses, are returned as they are. A grpc status wrapped by an
+// *errPkg.Error is never exposed. All others become codes.Internal.
+// ToStatus returns nil for nil
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	pkgErr, isPkgErr := errPkg.As(err)
+	if !isPkgErr {
+		var grpcErr interface{ GRPCStatus() *status.Status }
+		if errors.As(err, &grpcErr) {
+			return grpcErr.GRPCStatus()
+		}
+	}
+
+	code := errPkg.CodeOf(err)
+	st := status.New(ToGRPCCode(code), errPkg.PublicMessage(err))
+
+	var details errPkg.Details
+	if isPkgErr {
+		details = pkgErr.GetDetails()
+	}
+
+	withDetails, detailsErr := st.WithDetails(protoDetails(code, details)...)
+	if detailsErr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// FromStatus converts a grpc status to an *errPkg.Error. The code is taken from
+// the errdetails.ErrorInfo added by ToStatus, if it is known. Otherwise, it is
+// derived from the grpc code using FromGRPCCode. FromStatus returns nil for
+// nil and statuses with codes.OK
+func FromStatus(st *status.Status) *errPkg.Error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	code := FromGRPCCode(st.Code())
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorInfoDomain {
+			if def, ok := errPkg.LookupCodeName(strings.ToLower(info.GetReason())); ok {
+				code = def.Code
+			}
+		}
+	}
+
+	err := errPkg.New(code, st.Message(), st.Err())
+
+	for _, detail := range st.Details() {
+		switch typed := detail.(type) {
+		case *errdetails.ErrorInfo:
+			for key, value := range typed.GetMetadata() {
+				err = err.WithMetadata(key, value)
+			}
+		case *errdetails.BadRequest:
+			for _, violation := range typed.GetFieldViolations() {
+				err = err.WithFieldViolation(violation.GetField(), violation.GetDescription())
+			}
+		case *errdetails.RetryInfo:
+			err = err.WithRetryAfter(typed.GetRetryDelay().AsDuration())
+		case *errdetails.Help:
+			for _, link := range typed.GetLinks() {
+				err = err.WithHelpLink(link.GetDescription(), link.GetUrl())
+			}
+		}
+	}
+
+	return err
+}
+
+// FromError converts err to an *errPkg.Error, if it is a grpc status
+// (see FromStatus). Otherwise, err is returned as it is
+func FromError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+
+	return FromStatus(st)
+}
+
+// reason returns the name of code in UPPER_SNAKE_CASE, as expected by
+// errdetails.ErrorInfo. It is derived from the registered name of code or
+// from its http status text, if it is not registered. Characters other than
+// letters and digits are replaced with underscores
+func reason(code errPkg.Code) string {
+	name := code.HttpStatusText()
+	if def, ok := errPkg.LookupCode(code); ok {
+		name = def.Name
+	}
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.ToUpper(strings.Join(words, "_"))
+}
+
+// protoDetails returns the grpc representation of the code and the details
+func protoDetails(code errPkg.Code, details errPkg.Details) []protoadapt.MessageV1 {
+	protoDetails := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   reason(code),
+			Domain:   ErrorInfoDomain,
+			Metadata: details.Metadata,
+		},
+	}
+
+	if len(details.FieldViolations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, violation := range details.FieldViolations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       violation.Field,
+				Description: violation.Description,
+			})
+		}
+
+		protoDetails = append(protoDetails, badRequest)
+	}
+
+	if details.RetryAfter > 0 {
+		protoDetails = append(protoDetails, &errdetails.RetryInfo{RetryDelay: durationpb.New(details.RetryAfter)})
+	}
+
+	if len(details.HelpLinks) > 0 {
+		help := &errdetails.Help{}
+		for _, link := range details.HelpLinks {
+			help.Links = append(help.Links, &errdetails.Help_Link{Description: link.Description, Url: link.URL})
+		}
+
+		protoDetails = append(protoDetails, help)
+	}
+
+	return protoDetails
+}
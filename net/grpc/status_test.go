@@ -0,0 +1,197 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+func TestToGRPCCode(t *testing.T) {
+	for code, expected := range map[errPkg.Code]codes.Code{
+		errPkg.CodeUser:               codes.InvalidArgument,
+		errPkg.CodeAuthRequired:       codes.Unauthenticated,
+		errPkg.CodeInvalidCredentials: codes.Unauthenticated,
+		errPkg.CodeForbidden:          codes.PermissionDenied,
+		errPkg.CodeNotFound:           codes.NotFound,
+		errPkg.CodeConflict:           codes.Aborted,
+		errPkg.CodeDuplicateKey:       codes.AlreadyExists,
+		errPkg.CodePreconditionFailed: codes.FailedPrecondition,
+		errPkg.CodeGone:               codes.NotFound,
+		errPkg.CodeTooManyRequests:    codes.ResourceExhausted,
+		errPkg.CodeInternal:           codes.Internal,
+		errPkg.CodeNotImplemented:     codes.Unimplemented,
+		errPkg.CodeUnavailable:        codes.Unavailable,
+		errPkg.CodeTimeout:            codes.DeadlineExceeded,
+		errPkg.Code(41800):            codes.InvalidArgument,
+		errPkg.Code(50200):            codes.Internal,
+	} {
+		t.Run(code.String(), func(t *testing.T) {
+			assert.Equal(t, expected, ToGRPCCode(code))
+		})
+	}
+}
+
+func TestFromGRPCCode(t *testing.T) {
+	for grpcCode, expected := range map[codes.Code]errPkg.Code{
+		codes.InvalidArgument:    errPkg.CodeUser,
+		codes.Unauthenticated:    errPkg.CodeAuthRequired,
+		codes.PermissionDenied:   errPkg.CodeForbidden,
+		codes.NotFound:           errPkg.CodeNotFound,
+		codes.AlreadyExists:      errPkg.CodeDuplicateKey,
+		codes.Aborted:            errPkg.CodeConflict,
+		codes.FailedPrecondition: errPkg.CodePreconditionFailed,
+		codes.ResourceExhausted:  errPkg.CodeTooManyRequests,
+		codes.Unimplemented:      errPkg.CodeNotImplemented,
+		codes.Unavailable:        errPkg.CodeUnavailable,
+		codes.DeadlineExceeded:   errPkg.CodeTimeout,
+		codes.Unknown:            errPkg.CodeInternal,
+		codes.DataLoss:           errPkg.CodeInternal,
+	} {
+		t.Run(grpcCode.String(), func(t *testing.T) {
+			assert.Equal(t, expected, FromGRPCCode(grpcCode))
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, ToStatus(nil))
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		st := ToStatus(errors.New("some error"))
+
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, errPkg.CodeInternal.Message(), st.Message())
+	})
+
+	t.Run("status", func(t *testing.T) {
+		st := status.New(codes.Unavailable, "unavailable")
+
+		assert.Equal(t, st, ToStatus(fmt.Errorf("wrapped: %w", st.Err())))
+	})
+
+	t.Run("error wrapping a status", func(t *testing.T) {
+		cause := status.Error(codes.Internal, "pq: relation users does not exist")
+
+		st := ToStatus(errPkg.New(errPkg.CodeNotFound, "user not found", cause))
+
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "user not found", st.Message())
+	})
+
+	t.Run("wrapped error with details", func(t *testing.T) {
+		err := errPkg.New(errPkg.CodeDuplicateKey, "duplicate email", nil).
+			WithFieldViolation("email", "is taken").
+			WithMetadata("resource", "user").
+			WithRetryAfter(time.Second*5).
+			WithHelpLink("docs", "https://example.com/docs")
+
+		st := ToStatus(fmt.Errorf("failed to create user: %w", err))
+
+		assert.Equal(t, codes.AlreadyExists, st.Code())
+		assert.Equal(t, "duplicate email", st.Message())
+
+		details := st.Details()
+		require.Len(t, details, 4)
+
+		info, ok := details[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, "DUPLICATE_KEY", info.GetReason())
+		assert.Equal(t, ErrorInfoDomain, info.GetDomain())
+		assert.Equal(t, map[string]string{"resource": "user"}, info.GetMetadata())
+
+		badRequest, ok := details[1].(*errdetails.BadRequest)
+		require.True(t, ok)
+		require.Len(t, badRequest.GetFieldViolations(), 1)
+		assert.Equal(t, "email", badRequest.GetFieldViolations()[0].GetField())
+
+		retryInfo, ok := details[2].(*errdetails.RetryInfo)
+		require.True(t, ok)
+		assert.Equal(t, time.Second*5, retryInfo.GetRetryDelay().AsDuration())
+
+		help, ok := details[3].(*errdetails.Help)
+		require.True(t, ok)
+		require.Len(t, help.GetLinks(), 1)
+		assert.Equal(t, "https://example.com/docs", help.GetLinks()[0].GetUrl())
+	})
+}
+
+func TestReason(t *testing.T) {
+	codeLocked := errPkg.MustRegisterCode(errPkg.CodeDefinition{
+		Code: 42301,
+		Name: "order-locked (v2)",
+	})
+
+	type testCase struct {
+		code errPkg.Code
+		exp  string
+	}
+
+	for name, tc := range map[string]testCase{
+		"registered":               {code: errPkg.CodeDuplicateKey, exp: "DUPLICATE_KEY"},
+		"registered special chars": {code: codeLocked, exp: "ORDER_LOCKED_V2"},
+		"unregistered":             {code: errPkg.Code(40001), exp: "BAD_REQUEST"},
+		"unregistered apostrophe":  {code: errPkg.Code(41800), exp: "I_M_A_TEAPOT"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.exp, reason(tc.code))
+		})
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert.Nil(t, FromStatus(nil))
+		assert.Nil(t, FromStatus(status.New(codes.OK, "")))
+	})
+
+	t.Run("without details", func(t *testing.T) {
+		err := FromStatus(status.New(codes.NotFound, "user not found"))
+
+		assert.Equal(t, errPkg.CodeNotFound, err.GetCode())
+		assert.Equal(t, "user not found", err.Error())
+	})
+
+	t.Run("foreign domain", func(t *testing.T) {
+		st, err := status.New(codes.Aborted, "conflict").WithDetails(&errdetails.ErrorInfo{
+			Reason: "DUPLICATE_KEY",
+			Domain: "example.com",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, errPkg.CodeConflict, FromStatus(st).GetCode())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		original := errPkg.New(errPkg.CodeInvalidCredentials, "invalid password", nil).
+			WithFieldViolation("password", "is wrong").
+			WithMetadata("attempts", "3").
+			WithRetryAfter(time.Minute).
+			WithHelpLink("docs", "https://example.com/docs")
+
+		err := FromStatus(ToStatus(original))
+
+		assert.Equal(t, errPkg.CodeInvalidCredentials, err.GetCode())
+		assert.Equal(t, "invalid password", err.Error())
+		assert.Equal(t, original.GetDetails(), err.GetDetails())
+	})
+}
+
+func TestFromError(t *testing.T) {
+	other := errors.New("some error")
+	assert.Equal(t, other, FromError(other))
+	assert.Nil(t, FromError(nil))
+
+	err := FromError(status.Error(codes.PermissionDenied, "forbidden"))
+	assert.True(t, errPkg.Is(err, errPkg.CodeForbidden))
+}
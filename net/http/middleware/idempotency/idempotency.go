@@ -0,0 +1,303 @@
+package idempotency
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	ctxPkg "github.com/tmeisel/glib/ctx"
+	errPkg "github.com/tmeisel/glib/error"
+
+	"github.com/tmeisel/glib/net/http/response"
+)
+
+const (
+	KeyHeader      = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	DefaultTTL         = time.Hour * 24
+	DefaultLockTimeout = time.Minute
+
+	// DefaultMaxBodySize is the default limit of request bodies in bytes
+	DefaultMaxBodySize int64 = 1 << 20
+)
+
+var (
+	ErrInProgress   = errPkg.New(errPkg.CodeConflict, "a request with the same idempotency key is in progress", nil)
+	ErrKeyReused    = errPkg.New(errPkg.CodeConflict, "idempotency key has been used for a different request", nil)
+	ErrBodyTooLarge = errPkg.New(errPkg.CodeUser, "request body is too large", nil)
+)
+
+// Store persists the responses of requests by their idempotency key. The
+// responses are opaque to the store
+type Store interface {
+	// Reserve stores fingerprint for key for the duration of ttl, if key is
+	// unknown. Otherwise, it returns the fingerprint and the response stored
+	// previously. An empty fingerprint means, that the key has been reserved
+	// by this call. A nil response means, that the request is in progress
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (string, []byte, error)
+
+	// Complete stores the response for a key reserved with fingerprint
+	// and keeps it for the duration of ttl
+	Complete(ctx context.Context, key, fingerprint string, response []byte, ttl time.Duration) error
+
+	// Release removes the reservation of key, if it is still reserved
+	// with fingerprint and no response has been stored
+	Release(ctx context.Context, key, fingerprint string) error
+}
+
+// Response is a response stored for an idempotency key
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+type IdempotencyMiddleware struct {
+	store       Store
+	ttl         time.Duration
+	lockTimeout time.Duration
+	maxBodySize int64
+}
+
+// NewIdempotencyMiddleware returns a middleware, that stores responses for
+// ttl. lockTimeout limits how long a key stays reserved for a request in
+// progress, e.g. if the process dies while handling it. It must be greater
+// than the time it takes to handle a request. Values less than or equal to
+// zero are replaced with DefaultTTL and DefaultLockTimeout
+func NewIdempotencyMiddleware(store Store, ttl, lockTimeout time.Duration) *IdempotencyMiddleware {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	if lockTimeout <= 0 {
+		lockTimeout = DefaultLockTimeout
+	}
+
+	return &IdempotencyMiddleware{
+		store:       store,
+		ttl:         ttl,
+		lockTimeout: lockTimeout,
+		maxBodySize: DefaultMaxBodySize,
+	}
+}
+
+// WithMaxBodySize returns a copy of the middleware, that rejects requests
+// with a body larger than size bytes with ErrBodyTooLarge and the status
+// 413. The body is read to fingerprint the request, so it must fit into
+// memory. A size less than or equal to zero is replaced with
+// DefaultMaxBodySize
+func (m *IdempotencyMiddleware) WithMaxBodySize(size int64) *IdempotencyMiddleware {
+	if size <= 0 {
+		size = DefaultMaxBodySize
+	}
+
+	c := *m
+	c.maxBodySize = size
+
+	return &c
+}
+
+// Middleware handles requests with an Idempotency-Key header only once and
+// replays the stored response for repeated requests. A repeated request must
+// have the same method, path and body as the first one. Otherwise, it fails
+// with ErrKeyReused. While the first request is in progress, repeated ones
+// fail with ErrInProgress. Responses with a status of 500 or above are not
+// stored, so the request can be retried.
+//
+// Keys are scoped by the identity of the request context, if there is one
+// (see auth.AuthMiddleware). The identity is formatted using fmt, so it should
+// implement fmt.Stringer
+func (m *IdempotencyMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(KeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if identity := ctxPkg.GetIdentity(r.Context()); identity != nil {
+			key = fmt.Sprintf("%v:%s", identity, key)
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, m.maxBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				response.WriteErrorStatus(w, http.StatusRequestEntityTooLarge, ErrBodyTooLarge)
+				return
+			}
+
+			response.WriteError(w, errPkg.NewUserMsg(err, "failed to read request body"))
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := fingerprint(r, body)
+
+		storedFingerprint, stored, err := m.store.Reserve(r.Context(), key, fingerprint, m.lockTimeout)
+		if err != nil {
+			response.WriteError(w, err)
+			return
+		}
+
+		if storedFingerprint != "" {
+			m.replay(w, fingerprint, storedFingerprint, stored)
+			return
+		}
+
+		m.handle(w, r, next, key, fingerprint)
+	})
+}
+
+// replay writes a stored response
+func (m *IdempotencyMiddleware) replay(w http.ResponseWriter, fingerprint, storedFingerprint string, stored []byte) {
+	if storedFingerprint != fingerprint {
+		response.WriteError(w, ErrKeyReused)
+		return
+	}
+
+	if stored == nil {
+		response.WriteError(w, ErrInProgress)
+		return
+	}
+
+	var res Response
+	if err := json.Unmarshal(stored, &res); err != nil {
+		response.WriteError(w, errPkg.NewInternalMsg(err, "failed to decode stored response"))
+		return
+	}
+
+	for name, values := range res.Header {
+		w.Header()[name] = values
+	}
+
+	w.Header().Set(ReplayedHeader, strconv.FormatBool(true))
+	w.WriteHeader(res.Status)
+	_, _ = w.Write(res.Body)
+}
+
+// handle calls next and stores its response. If next fails or panics,
+// the reservation is released
+func (m *IdempotencyMiddleware) handle(w http.ResponseWriter, r *http.Request, next http.Handler, key, fingerprint string) {
+	// the outcome has to be stored, even if the client went away
+	ctx := ctxPkg.Disconnect(r.Context())
+	rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+	completed := false
+	defer func() {
+		if !completed {
+			logError(ctx, m.store.Release(ctx, key, fingerprint))
+		}
+	}()
+
+	next.ServeHTTP(rec, r)
+
+	// a hijacked connection has no response, that could be replayed
+	if rec.hijacked || rec.status >= http.StatusInternalServerError {
+		return
+	}
+
+	stored, err := json.Marshal(Response{Status: rec.status, Header: rec.header, Body: rec.body.Bytes()})
+	if err != nil {
+		logError(ctx, err)
+		return
+	}
+
+	if err := m.store.Complete(ctx, key, fingerprint, stored, m.ttl); err != nil {
+		logError(ctx, err)
+		return
+	}
+
+	completed = true
+}
+
+// fingerprint identifies a request by its method, path and body
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.RequestURI())
+	_, _ = h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func logError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	if logger := ctxPkg.GetLogger(ctx); logger != nil {
+		logger.Errorf(ctx, "idempotency: %v", err)
+	}
+}
+
+// recorder writes through to the ResponseWriter and keeps a copy
+// of the status, the headers and the body. Like the writers of the
+// response package, it forwards http.Flusher and http.Hijacker
+type recorder struct {
+	http.ResponseWriter
+
+	status      int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+	hijacked    bool
+}
+
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Flush flushes the wrapped writer, if it supports flushing
+func (r *recorder) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Hijack hijacks the connection of the wrapped writer. It returns
+// an error, if the wrapped writer does not support hijacking
+func (r *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil {
+		r.hijacked = true
+	}
+
+	return conn, rw, err
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.wroteHeader = true
+	r.status = status
+	r.header = r.ResponseWriter.Header().Clone()
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,276 @@
+package idempotency
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entry struct {
+	fingerprint string
+	response    []byte
+}
+
+// memoryStore is a Store for tests, that ignores ttls
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: make(map[string]entry)}
+}
+
+func (s *memoryStore) Reserve(_ context.Context, key, fingerprint string, _ time.Duration) (string, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		return e.fingerprint, e.response, nil
+	}
+
+	s.entries[key] = entry{fingerprint: fingerprint}
+
+	return "", nil, nil
+}
+
+func (s *memoryStore) Complete(_ context.Context, key, fingerprint string, response []byte, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = entry{fingerprint: fingerprint, response: response}
+
+	return nil
+}
+
+func (s *memoryStore) Release(_ context.Context, key, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	var calls int
+
+	handler := NewIdempotencyMiddleware(newMemoryStore(), time.Hour, time.Minute).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+
+			w.Header().Set("Location", "/items/1")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		}),
+	)
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+		if key != "" {
+			r.Header.Set(KeyHeader, key)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		return rec
+	}
+
+	rec := send("key", `{"name":"a"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get(ReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	// the stored response is replayed
+	rec = send("key", `{"name":"a"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/items/1", rec.Header().Get("Location"))
+	assert.Equal(t, "true", rec.Header().Get(ReplayedHeader))
+	assert.Equal(t, `{"id":1}`, rec.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// a different body must not reuse the key
+	rec = send("key", `{"name":"b"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, 1, calls)
+
+	// requests without key are not affected
+	send("", `{"name":"a"}`)
+	send("", `{"name":"a"}`)
+	assert.Equal(t, 3, calls)
+}
+
+func TestIdempotencyMiddleware_InProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	handler := NewIdempotencyMiddleware(newMemoryStore(), time.Hour, time.Minute).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+
+	send := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set(KeyHeader, "key")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		return rec
+	}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() {
+		first <- send()
+	}()
+
+	<-started
+	assert.Equal(t, http.StatusConflict, send().Code)
+
+	close(release)
+	assert.Equal(t, http.StatusNoContent, (<-first).Code)
+}
+
+func TestIdempotencyMiddleware_Release(t *testing.T) {
+	store := newMemoryStore()
+	status := http.StatusInternalServerError
+
+	handler := NewIdempotencyMiddleware(store, time.Hour, time.Minute).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if status == 0 {
+				panic("handler failed")
+			}
+
+			w.WriteHeader(status)
+		}),
+	)
+
+	send := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set(KeyHeader, "key")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		return rec
+	}
+
+	// failed requests may be retried
+	assert.Equal(t, http.StatusInternalServerError, send().Code)
+	assert.Empty(t, store.entries)
+
+	status = 0
+	require.Panics(t, func() { send() })
+	assert.Empty(t, store.entries)
+
+	status = http.StatusOK
+	assert.Equal(t, http.StatusOK, send().Code)
+	assert.Len(t, store.entries, 1)
+}
+
+func TestNewIdempotencyMiddleware(t *testing.T) {
+	m := NewIdempotencyMiddleware(newMemoryStore(), 0, -time.Second)
+	assert.Equal(t, DefaultTTL, m.ttl)
+	assert.Equal(t, DefaultLockTimeout, m.lockTimeout)
+	assert.Equal(t, DefaultMaxBodySize, m.maxBodySize)
+
+	m = NewIdempotencyMiddleware(newMemoryStore(), time.Hour, time.Minute)
+	assert.Equal(t, time.Hour, m.ttl)
+	assert.Equal(t, time.Minute, m.lockTimeout)
+}
+
+func TestIdempotencyMiddleware_MaxBodySize(t *testing.T) {
+	store := newMemoryStore()
+	var calls int
+
+	handler := NewIdempotencyMiddleware(store, time.Hour, time.Minute).WithMaxBodySize(4).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+
+	send := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set(KeyHeader, "key")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		return rec
+	}
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("12345").Code)
+	assert.Empty(t, store.entries)
+	assert.Equal(t, 0, calls)
+
+	assert.Equal(t, http.StatusNoContent, send("1234").Code)
+	assert.Equal(t, 1, calls)
+}
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+
+	hijacked bool
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+
+	return nil, nil, nil
+}
+
+func TestIdempotencyMiddleware_OptionalInterfaces(t *testing.T) {
+	store := newMemoryStore()
+	hijack := false
+
+	handler := NewIdempotencyMiddleware(store, time.Hour, time.Minute).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hijack {
+				hijacker, ok := w.(http.Hijacker)
+				require.True(t, ok)
+				_, _, err := hijacker.Hijack()
+				require.NoError(t, err)
+
+				return
+			}
+
+			flusher, ok := w.(http.Flusher)
+			require.True(t, ok)
+			flusher.Flush()
+		}),
+	)
+
+	send := func(key string) *hijackRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/events", nil)
+		r.Header.Set(KeyHeader, key)
+
+		rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+		handler.ServeHTTP(rec, r)
+
+		return rec
+	}
+
+	rec := send("flush")
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, store.entries, 1)
+
+	// the response of a hijacked connection is not stored
+	hijack = true
+	assert.True(t, send("hijack").hijacked)
+	assert.Len(t, store.entries, 1)
+}
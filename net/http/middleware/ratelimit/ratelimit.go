@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	ctxPkg "github.com/tmeisel/glib/ctx"
+	errPkg "github.com/tmeisel/glib/error"
+
+	"github.com/tmeisel/glib/net/http/request"
+	"github.com/tmeisel/glib/net/http/response"
+)
+
+const (
+	LimitHeader      = "RateLimit-Limit"
+	RemainingHeader  = "RateLimit-Remaining"
+	ResetHeader      = "RateLimit-Reset"
+	RetryAfterHeader = "Retry-After"
+)
+
+var (
+	ErrTooManyRequests = errPkg.New(errPkg.CodeTooManyRequests, "too many requests", nil)
+)
+
+// Result describes the state of a limit after a request
+type Result struct {
+	Allowed bool
+
+	// Limit is the maximum number of requests within a period
+	Limit int64
+
+	// Remaining is the number of requests, that
+	// would be allowed immediately afterward
+	Remaining int64
+
+	// Reset is the time until the limit is fully available again
+	Reset time.Duration
+
+	// RetryAfter is the time until the next request is
+	// allowed. It is only set, if the request was denied
+	RetryAfter time.Duration
+}
+
+// Limiter decides whether a request identified by key is allowed
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// LimiterFunc is an adapter to use a function as Limiter
+type LimiterFunc func(ctx context.Context, key string) (Result, error)
+
+func (fn LimiterFunc) Allow(ctx context.Context, key string) (Result, error) {
+	return fn(ctx, key)
+}
+
+// KeyFunc returns the key to limit the request by. If it
+// returns an empty string, the request is not limited
+type KeyFunc func(r *http.Request) string
+
+type RateLimitMiddleware struct {
+	limiter Limiter
+	keyFn   KeyFunc
+}
+
+func NewRateLimitMiddleware(limiter Limiter, keyFn KeyFunc) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiter: limiter,
+		keyFn:   keyFn,
+	}
+}
+
+// Middleware sets the RateLimit-* headers on every limited request
+// and rejects requests exceeding the limit with ErrTooManyRequests
+// and a Retry-After header
+func (m *RateLimitMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := m.keyFn(r)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		res, err := m.limiter.Allow(r.Context(), key)
+		if err != nil {
+			response.WriteError(w, err)
+			return
+		}
+
+		header := w.Header()
+		header.Set(LimitHeader, strconv.FormatInt(res.Limit, 10))
+		header.Set(RemainingHeader, strconv.FormatInt(res.Remaining, 10))
+		header.Set(ResetHeader, seconds(res.Reset))
+
+		if !res.Allowed {
+			header.Set(RetryAfterHeader, seconds(res.RetryAfter))
+			response.WriteError(w, ErrTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// KeyByIP limits requests by the IP address of the client. Behind a
+// proxy, the remote address must be rewritten first, e.g. by using
+// handlers.ProxyHeaders of github.com/gorilla/handlers
+func KeyByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return fmt.Sprintf("ip:%s", host)
+}
+
+// KeyByAPIKey limits requests by the X-API-Key header. The key is
+// hashed, so it's not exposed to the store. Requests without the
+// header are not limited
+func KeyByAPIKey(r *http.Request) string {
+	if apiKey := r.Header.Get(request.ApiKeyHeader); apiKey != "" {
+		sum := sha256.Sum256([]byte(apiKey))
+
+		return fmt.Sprintf("api-key:%s", hex.EncodeToString(sum[:]))
+	}
+
+	return ""
+}
+
+// KeyByIdentity limits requests by the identity of the request context
+// (see auth.AuthMiddleware). The identity is formatted using fmt, so it
+// should implement fmt.Stringer. Requests without identity are limited
+// by fallback, which may be nil
+func KeyByIdentity(fallback KeyFunc) KeyFunc {
+	return func(r *http.Request) string {
+		if identity := ctxPkg.GetIdentity(r.Context()); identity != nil {
+			return fmt.Sprintf("identity:%v", identity)
+		}
+
+		if fallback == nil {
+			return ""
+		}
+
+		return fallback(r)
+	}
+}
+
+// seconds formats d as number of seconds, rounded up
+func seconds(d time.Duration) string {
+	if d <= 0 {
+		return "0"
+	}
+
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
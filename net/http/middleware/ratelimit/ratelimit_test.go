@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ctxPkg "github.com/tmeisel/glib/ctx"
+
+	"github.com/tmeisel/glib/net/http/request"
+)
+
+type identity string
+
+func (i identity) String() string {
+	return string(i)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	type testCase struct {
+		Result     Result
+		Err        error
+		KeyFn      KeyFunc
+		Status     int
+		Headers    map[string]string
+		NoHeaders  bool
+		LimiterHit bool
+	}
+
+	for name, tc := range map[string]testCase{
+		"allowed": {
+			Result: Result{Allowed: true, Limit: 10, Remaining: 9, Reset: time.Millisecond * 1500},
+			KeyFn:  KeyByIP,
+			Status: http.StatusOK,
+			Headers: map[string]string{
+				LimitHeader:      "10",
+				RemainingHeader:  "9",
+				ResetHeader:      "2",
+				RetryAfterHeader: "",
+			},
+			LimiterHit: true,
+		},
+		"denied": {
+			Result: Result{Limit: 10, Reset: time.Second * 10, RetryAfter: time.Millisecond * 100},
+			KeyFn:  KeyByIP,
+			Status: http.StatusTooManyRequests,
+			Headers: map[string]string{
+				LimitHeader:      "10",
+				RemainingHeader:  "0",
+				ResetHeader:      "10",
+				RetryAfterHeader: "1",
+			},
+			LimiterHit: true,
+		},
+		"limiter error": {
+			Err:        errors.New("failed"),
+			KeyFn:      KeyByIP,
+			Status:     http.StatusInternalServerError,
+			NoHeaders:  true,
+			LimiterHit: true,
+		},
+		"no key": {
+			KeyFn:     KeyByAPIKey,
+			Status:    http.StatusOK,
+			NoHeaders: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var hit bool
+			limiter := LimiterFunc(func(ctx context.Context, key string) (Result, error) {
+				hit = true
+				return tc.Result, tc.Err
+			})
+
+			handler := NewRateLimitMiddleware(limiter, tc.KeyFn).Middleware(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}),
+			)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tc.Status, rec.Code)
+			assert.Equal(t, tc.LimiterHit, hit)
+
+			for header, value := range tc.Headers {
+				assert.Equal(t, value, rec.Header().Get(header), header)
+			}
+
+			if tc.NoHeaders {
+				assert.Empty(t, rec.Header().Get(LimitHeader))
+			}
+		})
+	}
+}
+
+func TestKeyFuncs(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+
+	assert.Equal(t, "ip:10.0.0.1", KeyByIP(r))
+	assert.Equal(t, "", KeyByAPIKey(r))
+	assert.Equal(t, "", KeyByIdentity(nil)(r))
+	assert.Equal(t, "ip:10.0.0.1", KeyByIdentity(KeyByIP)(r))
+
+	r.Header.Set(request.ApiKeyHeader, "secret")
+	// sha256 of "secret"
+	assert.Equal(t, "api-key:2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b", KeyByAPIKey(r))
+
+	r = r.WithContext(ctxPkg.WithIdentity(r.Context(), identity("user-1")))
+	assert.Equal(t, "identity:user-1", KeyByIdentity(KeyByIP)(r))
+}
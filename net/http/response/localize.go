@@ -0,0 +1,96 @@
+package response
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LanguageMiddleware lets WriteError and WriteErrorStatus localize the
+// messages of errors using the Accept-Language header of the request
+// (see errPkg.Error.LocalizedMessage)
+func LanguageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		languages := parseAcceptLanguage(r)
+		if len(languages) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(&languageWriter{responseWriter: responseWriter{w}, languages: languages}, r)
+	})
+}
+
+// languageWriter carries the languages accepted by the client
+type languageWriter struct {
+	responseWriter
+
+	languages []string
+}
+
+// getLanguages returns the languages accepted by the client, if w wraps
+// a languageWriter. The languages are ordered by preference
+func getLanguages(w http.ResponseWriter) []string {
+	if lw := findWriter[*languageWriter](w); lw != nil {
+		return lw.languages
+	}
+
+	return nil
+}
+
+// parseAcceptLanguage returns the lower case language tags of the
+// Accept-Language header of r ordered by their quality. Tags with
+// a quality of 0 and the wildcard are omitted
+func parseAcceptLanguage(r *http.Request) []string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []language
+
+	for _, accept := range r.Header.Values("Accept-Language") {
+		for _, languageRange := range strings.Split(accept, ",") {
+			tag, params, _ := strings.Cut(languageRange, ";")
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || tag == "*" {
+				continue
+			}
+
+			quality := 1.0
+			if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+				parsed, err := strconv.ParseFloat(q, 64)
+				if err != nil {
+					continue
+				}
+
+				quality = parsed
+			}
+
+			if quality <= 0 {
+				continue
+			}
+
+			languages = append(languages, language{tag: tag, quality: quality})
+		}
+	}
+
+	slices.SortStableFunc(languages, func(a, b language) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	tags := make([]string, 0, len(languages))
+	for _, language := range languages {
+		tags = append(tags, language.tag)
+	}
+
+	return tags
+}
@@ -0,0 +1,126 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+func init() {
+	errPkg.RegisterCatalog(errPkg.MapCatalog{
+		"de": {
+			"test_invalid_name": "Ungültiger Name %q",
+			"internal":          "Interner Fehler",
+		},
+		"en": {
+			"test_invalid_name": "invalid name %q",
+		},
+	})
+}
+
+func TestLanguageMiddleware(t *testing.T) {
+	type testCase struct {
+		Error          error
+		AcceptLanguage string
+		Expected       string
+	}
+
+	invalidName := errPkg.NewUserMsg(nil, "invalid name").WithMessageKey("test_invalid_name", "x")
+
+	for name, tc := range map[string]testCase{
+		"without accept language": {
+			Error:    invalidName,
+			Expected: "invalid name",
+		},
+		"german": {
+			Error:          invalidName,
+			AcceptLanguage: "de-DE,de;q=0.9,en;q=0.8",
+			Expected:       `Ungültiger Name "x"`,
+		},
+		"preferred english": {
+			Error:          invalidName,
+			AcceptLanguage: "de;q=0.5, en",
+			Expected:       `invalid name "x"`,
+		},
+		"unsupported language": {
+			Error:          invalidName,
+			AcceptLanguage: "fr",
+			Expected:       "invalid name",
+		},
+		"excluded language": {
+			Error:          invalidName,
+			AcceptLanguage: "de;q=0, fr",
+			Expected:       "invalid name",
+		},
+		"other error": {
+			Error:          errors.New("connection refused"),
+			AcceptLanguage: "de",
+			Expected:       "Interner Fehler",
+		},
+		"other error unsupported language": {
+			Error:          errors.New("connection refused"),
+			AcceptLanguage: "fr",
+			Expected:       http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := LanguageMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				WriteError(w, tc.Error)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.AcceptLanguage != "" {
+				r.Header.Set("Accept-Language", tc.AcceptLanguage)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+
+			var res response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			require.NotNil(t, res.Error)
+
+			assert.Equal(t, tc.Expected, res.Error.Message)
+		})
+	}
+}
+
+func TestLanguageMiddleware_OptionalInterfaces(t *testing.T) {
+	rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	handler := LanguageMiddleware(ErrorFormatMiddleware(ErrorFormatProblem)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"de"}, getLanguages(w))
+
+		flusher, ok := w.(http.Flusher)
+		require.True(t, ok)
+		flusher.Flush()
+
+		hijacker, ok := w.(http.Hijacker)
+		require.True(t, ok)
+		_, _, err := hijacker.Hijack()
+		require.NoError(t, err)
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept-Language", "de")
+
+	handler.ServeHTTP(rec, req)
+
+	assert.True(t, rec.Flushed)
+	assert.True(t, rec.hijacked)
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Add("Accept-Language", "fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5")
+	r.Header.Add("Accept-Language", "it;q=0.95, es;q=0, nl;q=invalid")
+
+	assert.Equal(t, []string{"fr-ch", "it", "fr", "en", "de"}, parseAcceptLanguage(r))
+}
@@ -0,0 +1,152 @@
+package response
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+// ErrorFormat selects how WriteError and WriteErrorStatus render errors
+type ErrorFormat string
+
+const (
+	// ErrorFormatEnvelope renders errors as {"success": false, "error": {...}}.
+	// It is the default
+	ErrorFormatEnvelope = ErrorFormat("envelope")
+
+	// ErrorFormatProblem renders errors as problem details (RFC 9457)
+	ErrorFormatProblem = ErrorFormat("problem")
+
+	// ErrorFormatNegotiate renders problem details, if the client accepts
+	// application/problem+json. Otherwise, the envelope is used
+	ErrorFormatNegotiate = ErrorFormat("negotiate")
+)
+
+const (
+	ContentTypeJSON    = "application/json"
+	ContentTypeProblem = "application/problem+json"
+)
+
+var (
+	problemTypeBaseURI string
+)
+
+// SetProblemTypeBaseURI sets the URI, that the type of problem details is
+// derived from, e.g. https://example.com/errors results in the type
+// https://example.com/errors/40901 for errPkg.CodeDuplicateKey. If no base
+// URI is set, the type is about:blank
+func SetProblemTypeBaseURI(uri string) {
+	problemTypeBaseURI = strings.TrimSuffix(uri, "/")
+}
+
+// Problem is the representation of an error as problem details (RFC 9457).
+// The members following Instance are extensions
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	Code            int                     `json:"code"`
+	FieldViolations []errPkg.FieldViolation `json:"fieldViolations,omitempty"`
+	Metadata        map[string]string       `json:"metadata,omitempty"`
+	RetryAfter      int64                   `json:"retryAfter,omitempty"`
+	HelpLinks       []errPkg.HelpLink       `json:"helpLinks,omitempty"`
+	Errors          []Error                 `json:"errors,omitempty"`
+	Debug           string                  `json:"debug,omitempty"`
+}
+
+func newProblem(status int, instance string, err Error) Problem {
+	problemType := "about:blank"
+	if problemTypeBaseURI != "" {
+		problemType = fmt.Sprintf("%s/%d", problemTypeBaseURI, err.Code)
+	}
+
+	return Problem{
+		Type:            problemType,
+		Title:           http.StatusText(status),
+		Status:          status,
+		Detail:          err.Message,
+		Instance:        instance,
+		Code:            err.Code,
+		FieldViolations: err.FieldViolations,
+		Metadata:        err.Metadata,
+		RetryAfter:      err.RetryAfter,
+		HelpLinks:       err.HelpLinks,
+		Errors:          err.Errors,
+		Debug:           err.Debug,
+	}
+}
+
+// ErrorFormatMiddleware lets WriteError and WriteErrorStatus render errors
+// of all requests passing through it in the given format
+func ErrorFormatMiddleware(format ErrorFormat) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			selected := format
+			if selected == ErrorFormatNegotiate {
+				selected = negotiateErrorFormat(r)
+			}
+
+			next.ServeHTTP(&formatWriter{responseWriter: responseWriter{w}, format: selected, instance: r.URL.Path}, r)
+		})
+	}
+}
+
+// formatWriter carries the error format selected for the request
+type formatWriter struct {
+	responseWriter
+
+	format   ErrorFormat
+	instance string
+}
+
+// getFormatWriter returns the formatWriter wrapped by w, if there is any
+func getFormatWriter(w http.ResponseWriter) *formatWriter {
+	return findWriter[*formatWriter](w)
+}
+
+// findWriter returns the first writer of type T in the chain
+// of writers wrapped by w. It returns the zero value of T, if
+// there is none
+func findWriter[T http.ResponseWriter](w http.ResponseWriter) T {
+	for {
+		if typed, ok := w.(T); ok {
+			return typed
+		}
+
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			var zero T
+			return zero
+		}
+
+		w = unwrapper.Unwrap()
+	}
+}
+
+// negotiateErrorFormat returns ErrorFormatProblem, if the
+// Accept header of r contains application/problem+json
+func negotiateErrorFormat(r *http.Request) ErrorFormat {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil || mediaType != ContentTypeProblem {
+				continue
+			}
+
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+				continue
+			}
+
+			return ErrorFormatProblem
+		}
+	}
+
+	return ErrorFormatEnvelope
+}
@@ -0,0 +1,146 @@
+package response
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+func TestErrorFormatMiddleware(t *testing.T) {
+	type testCase struct {
+		Format  ErrorFormat
+		Accept  string
+		Problem bool
+	}
+
+	for name, tc := range map[string]testCase{
+		"envelope": {
+			Format:  ErrorFormatEnvelope,
+			Accept:  ContentTypeProblem,
+			Problem: false,
+		},
+		"problem": {
+			Format:  ErrorFormatProblem,
+			Problem: true,
+		},
+		"negotiate without accept": {
+			Format:  ErrorFormatNegotiate,
+			Problem: false,
+		},
+		"negotiate json": {
+			Format:  ErrorFormatNegotiate,
+			Accept:  "application/json",
+			Problem: false,
+		},
+		"negotiate problem": {
+			Format:  ErrorFormatNegotiate,
+			Accept:  "application/json, application/problem+json;q=0.9",
+			Problem: true,
+		},
+		"negotiate problem not acceptable": {
+			Format:  ErrorFormatNegotiate,
+			Accept:  "application/json, application/problem+json;q=0",
+			Problem: false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := ErrorFormatMiddleware(tc.Format)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				WriteError(w, errPkg.NewUserMsg(nil, "invalid name").WithFieldViolation("name", "must not be empty"))
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/users?x=1", nil)
+			if tc.Accept != "" {
+				r.Header.Set("Accept", tc.Accept)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			if !tc.Problem {
+				assert.Equal(t, ContentTypeJSON, rec.Header().Get("Content-Type"))
+
+				var res response
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+				require.NotNil(t, res.Error)
+				assert.Equal(t, "invalid name", res.Error.Message)
+
+				return
+			}
+
+			assert.Equal(t, ContentTypeProblem, rec.Header().Get("Content-Type"))
+
+			var problem Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+
+			assert.Equal(t, Problem{
+				Type:            "about:blank",
+				Title:           http.StatusText(http.StatusBadRequest),
+				Status:          http.StatusBadRequest,
+				Detail:          "invalid name",
+				Instance:        "/users",
+				Code:            int(errPkg.CodeUser),
+				FieldViolations: []errPkg.FieldViolation{{Field: "name", Description: "must not be empty"}},
+			}, problem)
+		})
+	}
+}
+
+func TestSetProblemTypeBaseURI(t *testing.T) {
+	SetProblemTypeBaseURI("https://example.com/errors/")
+	defer SetProblemTypeBaseURI("")
+
+	handler := ErrorFormatMiddleware(ErrorFormatProblem)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, errPkg.New(errPkg.CodeDuplicateKey, "duplicate key", nil))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+
+	assert.Equal(t, "https://example.com/errors/40901", problem.Type)
+	assert.Equal(t, http.StatusConflict, problem.Status)
+}
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+
+	hijacked bool
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+
+	return nil, nil, nil
+}
+
+func TestErrorFormatMiddleware_OptionalInterfaces(t *testing.T) {
+	rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	handler := ErrorFormatMiddleware(ErrorFormatProblem)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		require.True(t, ok)
+		flusher.Flush()
+
+		hijacker, ok := w.(http.Hijacker)
+		require.True(t, ok)
+		_, _, err := hijacker.Hijack()
+		require.NoError(t, err)
+	}))
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assert.True(t, rec.Flushed)
+	assert.True(t, rec.hijacked)
+}
@@ -3,15 +3,19 @@ package response
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/tmeisel/glib/net/pagination"
 
 	errPkg "github.com/tmeisel/glib/error"
 	logPkg "github.com/tmeisel/glib/log"
+	"github.com/tmeisel/glib/log/fields"
 )
 
 type response struct {
@@ -22,30 +26,54 @@ type response struct {
 }
 
 type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code            int                     `json:"code"`
+	Message         string                  `json:"message"`
+	FieldViolations []errPkg.FieldViolation `json:"fieldViolations,omitempty"`
+	Metadata        map[string]string       `json:"metadata,omitempty"`
+
+	// RetryAfter is the number of seconds, after
+	// which the request may be retried
+	RetryAfter int64             `json:"retryAfter,omitempty"`
+	HelpLinks  []errPkg.HelpLink `json:"helpLinks,omitempty"`
+
+	// Errors holds the items of an *errPkg.MultiError
+	Errors []Error `json:"errors,omitempty"`
+
+	// Debug holds the internal message of the error in debug mode
+	Debug string `json:"debug,omitempty"`
 }
 
 var (
 	logger logPkg.Logger
+	debug  bool
 )
 
 func SetLogger(l logPkg.Logger) {
 	logger = l
 }
 
-func WriteError(w http.ResponseWriter, err error) {
-	status := http.StatusInternalServerError
-	if pkgErr, ok := err.(*errPkg.Error); ok {
-		status = pkgErr.GetStatus()
-	}
+// SetDebug enables or disables the debug mode. In debug mode, errors
+// include their internal message (see errPkg.InternalMessage), which may
+// contain sensitive details. It must not be enabled in production
+func SetDebug(enabled bool) {
+	debug = enabled
+}
 
-	WriteErrorStatus(w, status, err)
+// WriteError writes err with the http status of its code (see errPkg.CodeOf).
+// Errors without an *errPkg.Error in their chain are internal errors
+func WriteError(w http.ResponseWriter, err error) {
+	WriteErrorStatus(w, errPkg.CodeOf(err).HttpStatus(), err)
 }
 
+// WriteErrorStatus writes err with the given status. Only the public message
+// of err is exposed (see errPkg.Error.PublicMessage). Errors without an
+// *errPkg.Error in their chain are described by the http status text.
+// Messages are localized, if the request passed LanguageMiddleware
 func WriteErrorStatus(w http.ResponseWriter, status int, err error) {
+	pkgErr, isPkgErr := errPkg.As(err)
+
 	code := status
-	if pkgErr, ok := err.(*errPkg.Error); ok {
+	if isPkgErr {
 		code = int(pkgErr.GetCode())
 	}
 
@@ -55,13 +83,65 @@ func WriteErrorStatus(w http.ResponseWriter, status int, err error) {
 	}
 
 	if logger != nil && status >= http.StatusInternalServerError {
-		logger.Errorf(context.Background(), "[%d] %v", code, err)
+		logger.Errorf(context.Background(), "[%d] %s", code, errPkg.InternalMessage(err), fields.Error(err))
 	}
 
-	writeJson(w, status, response{Success: false, Error: &Error{
+	languages := getLanguages(w)
+
+	respErr := &Error{
 		Code:    code,
-		Message: err.Error(),
-	}})
+		Message: errPkg.Code(code).LocalizedMessage(languages...),
+	}
+
+	if isPkgErr {
+		respErr.Message = pkgErr.LocalizedMessage(languages...)
+	}
+
+	if debug {
+		respErr.Debug = errPkg.InternalMessage(err)
+	}
+
+	if isPkgErr {
+		respErr.setDetails(pkgErr.GetDetails())
+	}
+
+	var multiErr *errPkg.MultiError
+	if errors.As(err, &multiErr) {
+		respErr.setItems(multiErr.Errors(), languages)
+	}
+
+	if respErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(respErr.RetryAfter, 10))
+	}
+
+	if fw := getFormatWriter(w); fw != nil && fw.format == ErrorFormatProblem {
+		writeJsonContentType(w, ContentTypeProblem, status, newProblem(status, fw.instance, *respErr))
+		return
+	}
+
+	writeJson(w, status, response{Success: false, Error: respErr})
+}
+
+// setDetails adds the details to the error
+func (e *Error) setDetails(details errPkg.Details) {
+	e.FieldViolations = details.FieldViolations
+	e.Metadata = details.Metadata
+	e.HelpLinks = details.HelpLinks
+
+	if details.RetryAfter > 0 {
+		e.RetryAfter = int64(math.Ceil(details.RetryAfter.Seconds()))
+	}
+}
+
+// setItems adds the errors of a MultiError to the error
+func (e *Error) setItems(errs []*errPkg.Error, languages []string) {
+	e.Errors = make([]Error, 0, len(errs))
+	for _, err := range errs {
+		item := Error{Code: int(err.GetCode()), Message: err.LocalizedMessage(languages...)}
+		item.setDetails(err.GetDetails())
+
+		e.Errors = append(e.Errors, item)
+	}
 }
 
 func WriteJson(w http.ResponseWriter, status int, v any) {
@@ -87,7 +167,11 @@ func LastModified(w http.ResponseWriter, t time.Time) {
 }
 
 func writeJson(w http.ResponseWriter, status int, v any) {
-	w.Header().Add("Content-Type", "application/json")
+	writeJsonContentType(w, ContentTypeJSON, status, v)
+}
+
+func writeJsonContentType(w http.ResponseWriter, contentType string, status int, v any) {
+	w.Header().Add("Content-Type", contentType)
 	w.WriteHeader(status)
 
 	if err := json.NewEncoder(w).Encode(v); err != nil {
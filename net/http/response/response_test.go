@@ -3,10 +3,12 @@ package response
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -87,6 +89,16 @@ func TestWriteError(t *testing.T) {
 			ExpectedStatus: http.StatusConflict,
 			ExpectedCode:   int(errPkg.CodeDuplicateKey),
 		},
+		"wrapped pkg err": {
+			Error:          fmt.Errorf("failed to create user: %w", errPkg.New(errPkg.CodeNotFound, "not found", nil)),
+			ExpectedStatus: http.StatusNotFound,
+			ExpectedCode:   int(errPkg.CodeNotFound),
+		},
+		"joined pkg errs": {
+			Error:          errors.Join(errPkg.NewUser(nil), errPkg.New(errPkg.CodeConflict, "conflict", nil)),
+			ExpectedStatus: http.StatusConflict,
+			ExpectedCode:   int(errPkg.CodeConflict),
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			rec := httptest.NewRecorder()
@@ -110,3 +122,124 @@ func TestWriteError(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteError_Details(t *testing.T) {
+	err := errPkg.NewUserMsg(nil, "invalid request").
+		WithFieldViolation("name", "must not be empty").
+		WithMetadata("resource", "user").
+		WithRetryAfter(time.Millisecond*1500).
+		WithHelpLink("API docs", "https://example.com/docs")
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	var r response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+	require.NotNil(t, r.Error)
+
+	assert.Equal(t, Error{
+		Code:            int(errPkg.CodeUser),
+		Message:         "invalid request",
+		FieldViolations: []errPkg.FieldViolation{{Field: "name", Description: "must not be empty"}},
+		Metadata:        map[string]string{"resource": "user"},
+		RetryAfter:      2,
+		HelpLinks:       []errPkg.HelpLink{{Description: "API docs", URL: "https://example.com/docs"}},
+	}, *r.Error)
+
+	// details are omitted, if not set
+	rec = httptest.NewRecorder()
+	WriteError(rec, errPkg.NewUser(nil))
+
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+	assert.NotContains(t, rec.Body.String(), "fieldViolations")
+	assert.NotContains(t, rec.Body.String(), "retryAfter")
+}
+
+func TestWriteError_Multi(t *testing.T) {
+	multiErr := errPkg.NewMulti(
+		errPkg.New(errPkg.CodeNotFound, "user 1 not found", nil),
+		errPkg.NewUserMsg(nil, "invalid user 2").WithFieldViolation("email", "must not be empty"),
+	)
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, fmt.Errorf("batch failed: %w", multiErr))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var r response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+	require.NotNil(t, r.Error)
+
+	assert.Equal(t, Error{
+		Code:    int(errPkg.CodeUser),
+		Message: "user 1 not found; invalid user 2",
+		Errors: []Error{
+			{Code: int(errPkg.CodeNotFound), Message: "user 1 not found"},
+			{
+				Code:            int(errPkg.CodeUser),
+				Message:         "invalid user 2",
+				FieldViolations: []errPkg.FieldViolation{{Field: "email", Description: "must not be empty"}},
+			},
+		},
+	}, *r.Error)
+
+	// any internal error makes the whole request fail with 500
+	rec = httptest.NewRecorder()
+	WriteError(rec, multiErr.Append(errPkg.NewInternal(nil)))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestWriteError_PublicMessage(t *testing.T) {
+	dbErr := errors.New(`duplicate key value violates unique constraint "users_email_key"`)
+
+	type testCase struct {
+		Error           error
+		ExpectedMessage string
+		ExpectedDebug   string
+	}
+
+	for name, tc := range map[string]testCase{
+		"other error": {
+			Error:           fmt.Errorf("failed to insert user: %w", dbErr),
+			ExpectedMessage: http.StatusText(http.StatusInternalServerError),
+			ExpectedDebug:   "failed to insert user: " + dbErr.Error(),
+		},
+		"internal error": {
+			Error:           dbPkg.NewErrorMsg(dbErr, "failed to insert user"),
+			ExpectedMessage: http.StatusText(http.StatusInternalServerError),
+			ExpectedDebug:   "failed to insert user: " + dbErr.Error(),
+		},
+		"public message": {
+			Error:           errPkg.New(errPkg.CodeDuplicateKey, dbErr.Error(), dbErr).WithPublicMessage("email is taken"),
+			ExpectedMessage: "email is taken",
+			ExpectedDebug:   dbErr.Error() + ": " + dbErr.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, debugMode := range []bool{false, true} {
+				SetDebug(debugMode)
+
+				rec := httptest.NewRecorder()
+				WriteError(rec, tc.Error)
+
+				var r response
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+				require.NotNil(t, r.Error)
+
+				assert.Equal(t, tc.ExpectedMessage, r.Error.Message)
+
+				if debugMode {
+					assert.Equal(t, tc.ExpectedDebug, r.Error.Debug)
+				} else {
+					assert.NotContains(t, rec.Body.String(), "users_email_key")
+				}
+			}
+
+			SetDebug(false)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package response
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// responseWriter is embedded by the writers of the middlewares of this
+// package. It forwards the optional interfaces http.Flusher and
+// http.Hijacker, so wrapping does not break streaming responses or
+// connection upgrades like websockets
+type responseWriter struct {
+	http.ResponseWriter
+}
+
+func (w responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush flushes the wrapped writer, if it supports flushing
+func (w responseWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack hijacks the connection of the wrapped writer. It returns
+// an error, if the wrapped writer does not support hijacking
+func (w responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
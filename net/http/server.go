@@ -12,12 +12,16 @@ import (
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
+
+	"github.com/tmeisel/glib/net/http/response"
 )
 
 type Server struct {
 	certFile string
 	keyFile  string
 
+	errorFormat response.ErrorFormat
+
 	withCORS    bool
 	corsOptions *cors.Options
 
@@ -35,6 +39,10 @@ type ServerConfig struct {
 	CertFile     string         `envconfig:"CERT_FILE"`
 	KeyFile      string         `envconfig:"KEY_FILE"`
 
+	// ErrorFormat is one of envelope, problem and negotiate.
+	// See response.ErrorFormat
+	ErrorFormat response.ErrorFormat `envconfig:"ERROR_FORMAT" default:"envelope"`
+
 	// WithCORS cannot be applied via envconfig. It's typically
 	// set by the implementing package, depending on the purpose
 	// of the application
@@ -76,6 +84,10 @@ func NewServerFromConf(ctx context.Context, conf ServerConfig) *Server {
 		s.WithTLS(conf.CertFile, conf.KeyFile)
 	}
 
+	if conf.ErrorFormat != "" {
+		s.SetErrorFormat(conf.ErrorFormat)
+	}
+
 	s.withCORS = conf.WithCORS
 	if conf.CORSOptions != nil {
 		s.corsOptions = conf.CORSOptions
@@ -101,6 +113,12 @@ func (s *Server) SetIdleTimeout(t time.Duration) {
 	s.srv.IdleTimeout = t
 }
 
+// SetErrorFormat selects how response.WriteError renders errors
+// of requests handled by the server
+func (s *Server) SetErrorFormat(format response.ErrorFormat) {
+	s.errorFormat = format
+}
+
 func (s *Server) StrictSlash(value bool) {
 	s.router.StrictSlash(value)
 }
@@ -137,7 +155,7 @@ func (s *Server) ListenAndServe() error {
 	router := s.router
 	router.Use(s.mwf...)
 
-	s.srv.Handler = handlers.LoggingHandler(os.Stdout, s.applyCORS(router))
+	s.srv.Handler = handlers.LoggingHandler(os.Stdout, response.LanguageMiddleware(s.applyErrorFormat(s.applyCORS(router))))
 
 	if s.certFile != "" && s.keyFile != "" {
 		return s.srv.ListenAndServeTLS(s.certFile, s.keyFile)
@@ -146,6 +164,14 @@ func (s *Server) ListenAndServe() error {
 	return s.srv.ListenAndServe()
 }
 
+func (s *Server) applyErrorFormat(handler http.Handler) http.Handler {
+	if s.errorFormat == "" || s.errorFormat == response.ErrorFormatEnvelope {
+		return handler
+	}
+
+	return response.ErrorFormatMiddleware(s.errorFormat)(handler)
+}
+
 func (s *Server) applyCORS(handler http.Handler) http.Handler {
 	if !s.withCORS {
 		return handler
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +12,10 @@ import (
 	"github.com/rs/cors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	errPkg "github.com/tmeisel/glib/error"
+
+	"github.com/tmeisel/glib/net/http/response"
 )
 
 const (
@@ -101,3 +106,21 @@ func TestServer_WithCORS(t *testing.T) {
 
 	require.NoError(t, serverError)
 }
+
+func TestServer_SetErrorFormat(t *testing.T) {
+	s := NewServerFromConf(context.Background(), ServerConfig{
+		ListenAddr:  addr,
+		ListenPort:  port,
+		ErrorFormat: response.ErrorFormatProblem,
+	})
+
+	handler := s.applyErrorFormat(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.WriteError(w, errPkg.NewUser(nil))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, response.ContentTypeProblem, rec.Header().Get("Content-Type"))
+}
@@ -0,0 +1,92 @@
+package queue
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec converts values of type T to and from
+// the representation stored in a Queue
+type Codec[T any] interface {
+	Encode(v T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec encodes values using encoding/json
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+
+	return v, err
+}
+
+// GobCodec encodes values using encoding/gob. Interface
+// values must be registered with gob.Register
+type GobCodec[T any] struct{}
+
+func (GobCodec[T]) Encode(v T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (GobCodec[T]) Decode(data []byte) (T, error) {
+	var v T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v)
+
+	return v, err
+}
+
+// MsgpackCodec encodes values using MessagePack
+type MsgpackCodec[T any] struct{}
+
+func (MsgpackCodec[T]) Encode(v T) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (MsgpackCodec[T]) Decode(data []byte) (T, error) {
+	var v T
+	err := msgpack.Unmarshal(data, &v)
+
+	return v, err
+}
+
+// ProtoMessage is implemented by protobuf messages generated with
+// marshaling methods, e.g. by gogo/protobuf. Messages generated by
+// google.golang.org/protobuf can be wrapped to implement it using
+// proto.Marshal and proto.Unmarshal
+type ProtoMessage interface {
+	Marshal() ([]byte, error)
+	Unmarshal(data []byte) error
+}
+
+// ProtoCodec encodes protobuf messages. M is the message type and
+// T the pointer to it, that implements ProtoMessage, e.g.
+// ProtoCodec[pb.Event, *pb.Event]
+type ProtoCodec[M any, T interface {
+	*M
+	ProtoMessage
+}] struct{}
+
+func (ProtoCodec[M, T]) Encode(v T) ([]byte, error) {
+	return v.Marshal()
+}
+
+func (ProtoCodec[M, T]) Decode(data []byte) (T, error) {
+	v := T(new(M))
+	err := v.Unmarshal(data)
+
+	return v, err
+}
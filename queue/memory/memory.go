@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tmeisel/glib/queue"
+)
+
+// Queue is an in-memory, goroutine-safe queue.Queue. It is
+// meant for tests and applications running in a single process.
+// The zero value is an empty queue, that does not block on Pop
+type Queue struct {
+	mu          sync.Mutex
+	values      []string
+	pushed      chan struct{}
+	readTimeout time.Duration
+}
+
+var (
+	_ queue.Queue        = &Queue{}
+	_ queue.ContextQueue = &Queue{}
+)
+
+// New returns an empty Queue. Pop blocks for up to readTimeout,
+// if the queue is empty. If readTimeout is not positive, Pop
+// returns immediately
+func New(readTimeout time.Duration) *Queue {
+	return &Queue{
+		pushed:      make(chan struct{}),
+		readTimeout: readTimeout,
+	}
+}
+
+func (q *Queue) Push(value string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.values = append(q.values, value)
+
+	// wake up all waiting consumers
+	if q.pushed != nil {
+		close(q.pushed)
+	}
+
+	q.pushed = make(chan struct{})
+
+	return nil
+}
+
+func (q *Queue) Pop() (string, error) {
+	return q.PopContext(context.Background())
+}
+
+func (q *Queue) Empty() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.values = nil
+
+	return nil
+}
+
+func (q *Queue) PushContext(ctx context.Context, value string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return q.Push(value)
+}
+
+// PopContext returns the oldest element of the queue. If the queue
+// is empty, it blocks until an element is pushed, the read timeout
+// is exceeded or ctx is done
+func (q *Queue) PopContext(ctx context.Context) (string, error) {
+	timer := time.NewTimer(q.readTimeout)
+	defer timer.Stop()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		value, pushed, ok := q.tryPop()
+		if ok {
+			return value, nil
+		}
+
+		if q.readTimeout <= 0 {
+			return "", queue.ErrEmpty
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timer.C:
+			return "", queue.ErrEmpty
+		case <-pushed:
+		}
+	}
+}
+
+func (q *Queue) EmptyContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return q.Empty()
+}
+
+// Len returns the number of elements in the queue
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.values)
+}
+
+// tryPop returns the oldest element, if there is any. Otherwise,
+// it returns a channel that is closed on the next push
+func (q *Queue) tryPop() (string, <-chan struct{}, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.values) == 0 {
+		if q.pushed == nil {
+			q.pushed = make(chan struct{})
+		}
+
+		return "", q.pushed, false
+	}
+
+	value := q.values[0]
+	q.values = q.values[1:]
+
+	return value, nil, true
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tmeisel/glib/queue"
+	"github.com/tmeisel/glib/queue/queuetest"
+)
+
+func TestConformance(t *testing.T) {
+	queuetest.Run(t, func(t *testing.T) queue.Queue {
+		return New(time.Second)
+	})
+}
+
+func TestQueue_ReadTimeout(t *testing.T) {
+	q := New(time.Millisecond * 50)
+
+	start := time.Now()
+	_, err := q.Pop()
+	require.ErrorIs(t, err, queue.ErrEmpty)
+	assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*50)
+}
+
+func TestQueue_NonBlocking(t *testing.T) {
+	q := New(0)
+
+	_, err := q.Pop()
+	require.ErrorIs(t, err, queue.ErrEmpty)
+
+	require.NoError(t, q.Push("hello"))
+	assert.Equal(t, 1, q.Len())
+
+	val, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", val)
+}
+
+func TestQueue_ZeroValue(t *testing.T) {
+	var q Queue
+
+	_, err := q.Pop()
+	require.ErrorIs(t, err, queue.ErrEmpty)
+
+	require.NoError(t, q.Push("hello"))
+
+	val, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", val)
+}
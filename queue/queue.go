@@ -1,9 +1,16 @@
 package queue
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"time"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
 
 var (
-	ErrEmpty = errors.New("queue is empty")
+	ErrEmpty         = errors.New("queue is empty")
+	ErrMaxDeliveries = errPkg.New(errPkg.CodeGone, "maximum number of deliveries exceeded", nil)
 )
 
 type Queue interface {
@@ -14,3 +21,152 @@ type Queue interface {
 	// Empty removes all elements from the queue
 	Empty() error
 }
+
+// ContextQueue is the context-aware variant of Queue. Blocking
+// operations return once ctx is cancelled or its deadline is exceeded
+type ContextQueue interface {
+	// PushContext adds an element to the queue
+	PushContext(ctx context.Context, value string) error
+	// PopContext returns a single element from the queue
+	PopContext(ctx context.Context) (string, error)
+	// EmptyContext removes all elements from the queue
+	EmptyContext(ctx context.Context) error
+}
+
+// BatchQueue is a Queue, that pushes and pops multiple
+// elements with a single round trip
+type BatchQueue interface {
+	Queue
+	// PushBatch adds all values to the queue, preserving their order
+	PushBatch(values []string) error
+	// PopBatch returns up to max elements from the queue
+	PopBatch(max int) ([]string, error)
+}
+
+// PriorityQueue is a Queue, whose Pop returns an element
+// of the highest priority available
+type PriorityQueue interface {
+	Queue
+	// PushPriority adds an element with the given priority.
+	// Higher values are more urgent
+	PushPriority(value string, priority int) error
+}
+
+// Message is an element received from a ReliableQueue. It
+// stays in flight until it is either acknowledged or rejected
+type Message interface {
+	// Value returns the content of the message
+	Value() string
+	// Ack marks the message as processed and removes it
+	// from the queue
+	Ack() error
+	// Nack rejects the message, so it will be delivered again.
+	// Messages of a StreamQueue stay pending, until they're claimed
+	Nack() error
+}
+
+// ReliableQueue is a Queue that keeps received elements until
+// they are acknowledged. Elements of consumers that crashed or
+// exceeded their visibility timeout are returned to the queue
+// by Requeue
+type ReliableQueue interface {
+	Queue
+	// Receive returns a single element from the queue, which
+	// must either be acknowledged or rejected by the caller
+	Receive() (Message, error)
+	// Requeue returns all elements whose visibility timeout
+	// expired to the queue. It returns the number of elements
+	// that have been requeued
+	Requeue() (int, error)
+}
+
+// DeadLetterMessage is a Message that can be moved to a
+// DeadLetterQueue, e.g. because it cannot be processed
+type DeadLetterMessage interface {
+	Message
+	// Deliveries returns how often the message has been
+	// delivered, including the current delivery
+	Deliveries() int
+	// DeadLetter removes the message from the queue and moves
+	// it to the dead-letter queue. The code and message of
+	// reason are preserved
+	DeadLetter(reason error) error
+}
+
+// DeadLetter is an element that has been moved to a DeadLetterQueue
+type DeadLetter struct {
+	Value      string      `json:"value"`
+	Code       errPkg.Code `json:"code"`
+	Reason     string      `json:"reason"`
+	Deliveries int         `json:"deliveries"`
+	Time       time.Time   `json:"time"`
+}
+
+// NewDeadLetter returns a DeadLetter for the given value. If reason
+// is an *errPkg.Error, its code is preserved. Otherwise, the code
+// defaults to errPkg.CodeInternal
+func NewDeadLetter(value string, reason error, deliveries int) DeadLetter {
+	dl := DeadLetter{
+		Value:      value,
+		Code:       errPkg.CodeInternal,
+		Deliveries: deliveries,
+		Time:       time.Now(),
+	}
+
+	if reason != nil {
+		dl.Reason = reason.Error()
+	}
+
+	var pkgErr *errPkg.Error
+	if errors.As(reason, &pkgErr) {
+		dl.Code = pkgErr.GetCode()
+	}
+
+	return dl
+}
+
+// DeadLetterQueue holds the elements of a queue that
+// could not be processed
+type DeadLetterQueue interface {
+	// DeadLetters returns up to limit dead-lettered elements,
+	// starting at offset. The most recent element comes first
+	DeadLetters(offset, limit int) ([]DeadLetter, error)
+	// Replay pushes up to n of the oldest dead-lettered elements
+	// back to the queue. It returns the number of replayed elements
+	Replay(n int) (int, error)
+	// Purge removes all dead-lettered elements
+	Purge() error
+}
+
+// PendingMessage describes a message of a StreamQueue, that
+// has been delivered to a consumer, but not yet acknowledged
+type PendingMessage struct {
+	ID         string
+	Consumer   string
+	Idle       time.Duration
+	Deliveries int64
+}
+
+// StreamMessage is a Message received from a StreamQueue
+type StreamMessage interface {
+	Message
+	// ID returns the id of the entry in the stream
+	ID() string
+}
+
+// StreamQueue is a Queue backed by an append-only log. Every element
+// is delivered to each consumer group once, so multiple groups can
+// consume the same elements independently
+type StreamQueue interface {
+	Queue
+	// Receive returns a single element, that has not yet been
+	// delivered to the consumer group. It stays pending until
+	// it is acknowledged
+	Receive() (Message, error)
+	// Pending returns up to count messages of the consumer group,
+	// that have been delivered, but not yet acknowledged
+	Pending(count int64) ([]PendingMessage, error)
+	// Claim transfers up to count pending messages, that have been
+	// idle for at least minIdle, to the consumer and returns them
+	Claim(minIdle time.Duration, count int64) ([]Message, error)
+}
@@ -0,0 +1,51 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+func TestNewDeadLetter(t *testing.T) {
+	type testCase struct {
+		Reason         error
+		ExpectedCode   errPkg.Code
+		ExpectedReason string
+	}
+
+	for name, tc := range map[string]testCase{
+		"no reason": {
+			Reason:         nil,
+			ExpectedCode:   errPkg.CodeInternal,
+			ExpectedReason: "",
+		},
+		"other error": {
+			Reason:         errors.New("some error"),
+			ExpectedCode:   errPkg.CodeInternal,
+			ExpectedReason: "some error",
+		},
+		"pkg error": {
+			Reason:         errPkg.NewUserMsg(nil, "invalid payload"),
+			ExpectedCode:   errPkg.CodeUser,
+			ExpectedReason: "invalid payload",
+		},
+		"max deliveries": {
+			Reason:         ErrMaxDeliveries,
+			ExpectedCode:   errPkg.CodeGone,
+			ExpectedReason: ErrMaxDeliveries.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dl := NewDeadLetter("value", tc.Reason, 3)
+
+			assert.Equal(t, "value", dl.Value)
+			assert.Equal(t, tc.ExpectedCode, dl.Code)
+			assert.Equal(t, tc.ExpectedReason, dl.Reason)
+			assert.Equal(t, 3, dl.Deliveries)
+			assert.False(t, dl.Time.IsZero())
+		})
+	}
+}
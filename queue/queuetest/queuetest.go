@@ -0,0 +1,168 @@
+package queuetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tmeisel/glib/queue"
+)
+
+// NewQueueFn must return a new, empty queue for every call. Pop
+// must block for at least a second, if the queue is empty
+type NewQueueFn func(t *testing.T) queue.Queue
+
+// Run runs the conformance test suite, that every queue.Queue
+// implementation should pass, against the queues returned by
+// newQueue
+func Run(t *testing.T, newQueue NewQueueFn) {
+	t.Run("empty", func(t *testing.T) {
+		testEmpty(t, newQueue(t))
+	})
+
+	t.Run("fifo", func(t *testing.T) {
+		testFIFO(t, newQueue(t))
+	})
+
+	t.Run("empty removes all", func(t *testing.T) {
+		testEmptyRemovesAll(t, newQueue(t))
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		testConcurrent(t, newQueue(t))
+	})
+
+	t.Run("blocking pop", func(t *testing.T) {
+		testBlockingPop(t, newQueue(t))
+	})
+
+	t.Run("context", func(t *testing.T) {
+		q, ok := newQueue(t).(queue.ContextQueue)
+		if !ok {
+			t.Skip("queue does not implement queue.ContextQueue")
+		}
+
+		testContext(t, q)
+	})
+}
+
+func testEmpty(t *testing.T, q queue.Queue) {
+	_, err := q.Pop()
+	require.ErrorIs(t, err, queue.ErrEmpty)
+}
+
+func testFIFO(t *testing.T, q queue.Queue) {
+	for i := 0; i < 3; i++ {
+		require.NoError(t, q.Push(fmt.Sprintf("value-%d", i)))
+	}
+
+	for i := 0; i < 3; i++ {
+		val, err := q.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("value-%d", i), val)
+	}
+
+	_, err := q.Pop()
+	require.ErrorIs(t, err, queue.ErrEmpty)
+}
+
+func testEmptyRemovesAll(t *testing.T, q queue.Queue) {
+	require.NoError(t, q.Push("a"))
+	require.NoError(t, q.Push("b"))
+
+	require.NoError(t, q.Empty())
+
+	_, err := q.Pop()
+	require.ErrorIs(t, err, queue.ErrEmpty)
+}
+
+func testConcurrent(t *testing.T, q queue.Queue) {
+	const (
+		producers = 4
+		perWorker = 25
+	)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+
+		go func(p int) {
+			defer wg.Done()
+
+			for i := 0; i < perWorker; i++ {
+				assert.NoError(t, q.Push(fmt.Sprintf("%d-%d", p, i)))
+			}
+		}(p)
+	}
+
+	var mu sync.Mutex
+	received := make(map[string]int)
+
+	for c := 0; c < producers; c++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < perWorker; i++ {
+				val, err := q.Pop()
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				mu.Lock()
+				received[val]++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.Len(t, received, producers*perWorker)
+	for val, n := range received {
+		assert.Equal(t, 1, n, "received %s more than once", val)
+	}
+}
+
+func testBlockingPop(t *testing.T, q queue.Queue) {
+	result := make(chan string, 1)
+
+	go func() {
+		val, err := q.Pop()
+		assert.NoError(t, err)
+		result <- val
+	}()
+
+	time.Sleep(time.Millisecond * 100)
+	require.NoError(t, q.Push("late"))
+
+	select {
+	case val := <-result:
+		assert.Equal(t, "late", val)
+	case <-time.After(time.Second * 5):
+		t.Fatal("blocking pop did not return")
+	}
+}
+
+func testContext(t *testing.T, q queue.ContextQueue) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	require.NoError(t, q.PushContext(ctx, "hello"))
+
+	val, err := q.PopContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", val)
+
+	cancel()
+
+	_, err = q.PopContext(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, q.PushContext(ctx, "hello"), context.Canceled)
+	require.ErrorIs(t, q.EmptyContext(ctx), context.Canceled)
+}
@@ -9,6 +9,12 @@ import (
 // Queue is a wrapper for redisPkg.Queue
 type Queue = redisPkg.Queue
 
+// ReliableQueue is a wrapper for redisPkg.ReliableQueue
+type ReliableQueue = redisPkg.ReliableQueue
+
+// Stream is a wrapper for redisPkg.Stream
+type Stream = redisPkg.Stream
+
 func New(conf redisPkg.Config, name string, readTimeout time.Duration) Queue {
 	r := redisPkg.New(conf)
 	return r.Queue(name, readTimeout)
@@ -17,3 +23,21 @@ func New(conf redisPkg.Config, name string, readTimeout time.Duration) Queue {
 func NewFromClient(r redisPkg.Redis, name string, readTimeout time.Duration) Queue {
 	return r.Queue(name, readTimeout)
 }
+
+func NewReliable(conf redisPkg.Config, name, consumer string, readTimeout, visibilityTimeout time.Duration) ReliableQueue {
+	r := redisPkg.New(conf)
+	return r.ReliableQueue(name, consumer, readTimeout, visibilityTimeout)
+}
+
+func NewReliableFromClient(r redisPkg.Redis, name, consumer string, readTimeout, visibilityTimeout time.Duration) ReliableQueue {
+	return r.ReliableQueue(name, consumer, readTimeout, visibilityTimeout)
+}
+
+func NewStream(conf redisPkg.Config, name, group, consumer string, readTimeout time.Duration) Stream {
+	r := redisPkg.New(conf)
+	return r.Stream(name, group, consumer, readTimeout)
+}
+
+func NewStreamFromClient(r redisPkg.Redis, name, group, consumer string, readTimeout time.Duration) Stream {
+	return r.Stream(name, group, consumer, readTimeout)
+}
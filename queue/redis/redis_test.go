@@ -11,6 +11,7 @@ import (
 
 	"github.com/tmeisel/glib/clients/redis/docker"
 	"github.com/tmeisel/glib/queue"
+	"github.com/tmeisel/glib/queue/queuetest"
 )
 
 var (
@@ -39,3 +40,33 @@ func TestNewFromClient(t *testing.T) {
 
 	assert.Implements(t, (*queue.Queue)(nil), client)
 }
+
+func TestNewReliable(t *testing.T) {
+	client := NewReliable(container.GetConfig(), uuid.NewString(), uuid.NewString(), time.Second, time.Second*5)
+
+	assert.Implements(t, (*queue.ReliableQueue)(nil), client)
+}
+
+func TestNewReliableFromClient(t *testing.T) {
+	client := NewReliableFromClient(container.GetClient(), uuid.NewString(), uuid.NewString(), time.Second, time.Second*5)
+
+	assert.Implements(t, (*queue.ReliableQueue)(nil), client)
+}
+
+func TestConformance(t *testing.T) {
+	queuetest.Run(t, func(t *testing.T) queue.Queue {
+		return New(container.GetConfig(), uuid.NewString(), time.Second)
+	})
+}
+
+func TestNewStream(t *testing.T) {
+	client := NewStream(container.GetConfig(), uuid.NewString(), uuid.NewString(), uuid.NewString(), time.Second)
+
+	assert.Implements(t, (*queue.StreamQueue)(nil), client)
+}
+
+func TestNewStreamFromClient(t *testing.T) {
+	client := NewStreamFromClient(container.GetClient(), uuid.NewString(), uuid.NewString(), uuid.NewString(), time.Second)
+
+	assert.Implements(t, (*queue.StreamQueue)(nil), client)
+}
@@ -0,0 +1,62 @@
+package queue
+
+import (
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+// Typed wraps a Queue and converts its elements
+// to and from T using a Codec
+type Typed[T any] struct {
+	queue Queue
+	codec Codec[T]
+}
+
+// NewTyped returns a Typed queue for the given Queue q. If codec
+// is nil, JSONCodec is used
+func NewTyped[T any](q Queue, codec Codec[T]) Typed[T] {
+	if codec == nil {
+		codec = JSONCodec[T]{}
+	}
+
+	return Typed[T]{queue: q, codec: codec}
+}
+
+// Queue returns the underlying Queue
+func (t Typed[T]) Queue() Queue {
+	return t.queue
+}
+
+// Push encodes v and adds it to the queue. Encoding
+// failures are returned as internal errors
+func (t Typed[T]) Push(v T) error {
+	data, err := t.codec.Encode(v)
+	if err != nil {
+		return errPkg.NewInternalMsg(err, "failed to encode value")
+	}
+
+	return t.queue.Push(string(data))
+}
+
+// Pop returns a single decoded element from the queue. If the element
+// cannot be decoded, a user error is returned, as the element has
+// been pushed in an invalid format
+func (t Typed[T]) Pop() (T, error) {
+	var v T
+
+	value, err := t.queue.Pop()
+	if err != nil {
+		return v, err
+	}
+
+	v, err = t.codec.Decode([]byte(value))
+	if err != nil {
+		return v, errPkg.NewUserMsg(err, "failed to decode value")
+	}
+
+	return v, nil
+}
+
+// Empty removes all elements from the queue
+func (t Typed[T]) Empty() error {
+	return t.queue.Empty()
+}
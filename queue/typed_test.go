@@ -0,0 +1,111 @@
+package queue
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	errPkg "github.com/tmeisel/glib/error"
+)
+
+type sliceQueue struct {
+	values []string
+}
+
+func (s *sliceQueue) Push(value string) error {
+	s.values = append(s.values, value)
+	return nil
+}
+
+func (s *sliceQueue) Pop() (string, error) {
+	if len(s.values) == 0 {
+		return "", ErrEmpty
+	}
+
+	value := s.values[0]
+	s.values = s.values[1:]
+
+	return value, nil
+}
+
+func (s *sliceQueue) Empty() error {
+	s.values = nil
+	return nil
+}
+
+type event struct {
+	ID   int    `json:"id" msgpack:"id"`
+	Name string `json:"name" msgpack:"name"`
+}
+
+// protoEvent mimics a generated protobuf message
+type protoEvent struct {
+	event
+}
+
+func (p *protoEvent) Marshal() ([]byte, error) {
+	return json.Marshal(p.event)
+}
+
+func (p *protoEvent) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, &p.event)
+}
+
+func TestTyped(t *testing.T) {
+	type testCase struct {
+		Codec Codec[event]
+	}
+
+	for name, tc := range map[string]testCase{
+		"default": {Codec: nil},
+		"json":    {Codec: JSONCodec[event]{}},
+		"gob":     {Codec: GobCodec[event]{}},
+		"msgpack": {Codec: MsgpackCodec[event]{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			q := NewTyped[event](&sliceQueue{}, tc.Codec)
+
+			input := event{ID: 1, Name: "created"}
+			require.NoError(t, q.Push(input))
+
+			output, err := q.Pop()
+			require.NoError(t, err)
+			assert.Equal(t, input, output)
+
+			_, err = q.Pop()
+			require.ErrorIs(t, err, ErrEmpty)
+		})
+	}
+}
+
+func TestTyped_Proto(t *testing.T) {
+	q := NewTyped[*protoEvent](&sliceQueue{}, ProtoCodec[protoEvent, *protoEvent]{})
+
+	input := &protoEvent{event{ID: 1, Name: "created"}}
+	require.NoError(t, q.Push(input))
+
+	output, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, input, output)
+}
+
+func TestTyped_DecodeError(t *testing.T) {
+	inner := &sliceQueue{}
+	q := NewTyped[event](inner, JSONCodec[event]{})
+
+	require.NoError(t, inner.Push("no json"))
+
+	_, err := q.Pop()
+	require.Error(t, err)
+	assert.True(t, errPkg.Is(err, errPkg.CodeUser))
+}
+
+func TestTyped_EncodeError(t *testing.T) {
+	q := NewTyped[func()](&sliceQueue{}, JSONCodec[func()]{})
+
+	err := q.Push(func() {})
+	require.Error(t, err)
+	assert.True(t, errPkg.Is(err, errPkg.CodeInternal))
+}
@@ -0,0 +1,240 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ctxPkg "github.com/tmeisel/glib/ctx"
+	errPkg "github.com/tmeisel/glib/error"
+	"github.com/tmeisel/glib/exec/backoff"
+	"github.com/tmeisel/glib/queue"
+)
+
+const (
+	DefaultConcurrency = 1
+	DefaultIdleTimeout = time.Millisecond * 100
+)
+
+// errRetryInterrupted is returned by Worker.handle, if the
+// retries of an element have been interrupted by shutdown
+var errRetryInterrupted = errors.New("retry interrupted by shutdown")
+
+// HandlerFunc processes a single element of the queue. To retry
+// an element, wrap the returned error with backoff.RetryableError
+type HandlerFunc func(ctx context.Context, value string) error
+
+type OptionFn func(w *Worker)
+
+// Worker runs concurrent consumers over a queue.Queue
+type Worker struct {
+	queue       queue.Queue
+	handler     HandlerFunc
+	concurrency int
+	backoffFn   func() *backoff.Backoff
+	idleTimeout time.Duration
+}
+
+// New returns a Worker that passes the elements of q to handler.
+// By default, a single consumer is run and failed elements are
+// not retried
+func New(q queue.Queue, handler HandlerFunc, options ...OptionFn) *Worker {
+	w := &Worker{
+		queue:       q,
+		handler:     handler,
+		concurrency: DefaultConcurrency,
+		idleTimeout: DefaultIdleTimeout,
+	}
+
+	for _, opt := range options {
+		opt(w)
+	}
+
+	return w
+}
+
+// WithConcurrency sets the number of concurrent consumers
+func WithConcurrency(n int) OptionFn {
+	return func(w *Worker) {
+		if n < 1 {
+			n = 1
+		}
+
+		w.concurrency = n
+	}
+}
+
+// WithBackoff retries elements, whose handler returned a
+// backoff.RetryableError, according to the backoff returned by fn.
+// fn is called for every element, as limits like backoff.WithMaxRetries
+// are not reset between calls
+func WithBackoff(fn func() *backoff.Backoff) OptionFn {
+	return func(w *Worker) {
+		w.backoffFn = fn
+	}
+}
+
+// WithIdleTimeout sets the time a consumer waits after the
+// queue was empty or returned an error
+func WithIdleTimeout(d time.Duration) OptionFn {
+	return func(w *Worker) {
+		w.idleTimeout = d
+	}
+}
+
+// Run starts the consumers and blocks until ctx is cancelled and all
+// of them finished their current element. Handlers receive a context
+// that is disconnected from ctx, so they're not interrupted on shutdown.
+//
+// If the queue implements queue.ReliableQueue, elements are acknowledged
+// after they have been handled successfully and rejected otherwise.
+// Elements of other queues are dropped, if they could not be handled,
+// unless their retries have been interrupted by cancelling ctx. Those
+// are pushed back to the queue.
+// If the queue implements queue.ContextQueue, waiting for an element is
+// interrupted by cancelling ctx
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			w.consume(ctx)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (w *Worker) consume(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := w.next(ctx)
+
+		switch {
+		case err == nil:
+			continue
+		case errors.Is(err, queue.ErrEmpty):
+			// idle
+		case ctx.Err() != nil:
+			return
+		default:
+			if logger := ctxPkg.GetLogger(ctx); logger != nil {
+				logger.Errorf(ctx, "failed to receive element: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.idleTimeout):
+		}
+	}
+}
+
+// next receives and handles a single element. It only returns
+// errors that occurred while receiving
+func (w *Worker) next(ctx context.Context) error {
+	if reliable, ok := w.queue.(queue.ReliableQueue); ok {
+		msg, err := reliable.Receive()
+		if err != nil {
+			return err
+		}
+
+		if err := w.handle(ctx, msg.Value()); err != nil {
+			w.logFailure(ctx, err)
+
+			if err := msg.Nack(); err != nil {
+				w.logFailure(ctx, err)
+			}
+
+			return nil
+		}
+
+		if err := msg.Ack(); err != nil {
+			w.logFailure(ctx, err)
+		}
+
+		return nil
+	}
+
+	value, err := w.pop(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := w.handle(ctx, value); err != nil {
+		w.logFailure(ctx, err)
+
+		// the element might still be handled successfully,
+		// so it's not dropped on shutdown
+		if errors.Is(err, errRetryInterrupted) {
+			if err := w.queue.Push(value); err != nil {
+				w.logFailure(ctx, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Worker) pop(ctx context.Context) (string, error) {
+	if ctxQueue, ok := w.queue.(queue.ContextQueue); ok {
+		return ctxQueue.PopContext(ctx)
+	}
+
+	return w.queue.Pop()
+}
+
+// handle calls the handler, retrying according to the backoff. The handler
+// runs on a context disconnected from ctx, but waiting for the next attempt
+// is interrupted, once ctx is done. In that case, the returned error wraps
+// errRetryInterrupted. The handler is called at least once
+func (w *Worker) handle(ctx context.Context, value string) error {
+	handlerCtx := ctxPkg.Disconnect(ctx)
+
+	if w.backoffFn == nil {
+		return w.call(handlerCtx, value)
+	}
+
+	var last error
+	attempted := false
+
+	err := w.backoffFn().Do(ctx, func(context.Context) error {
+		attempted = true
+		last = w.call(handlerCtx, value)
+
+		return last
+	})
+
+	switch {
+	case !attempted:
+		// ctx was done before the first attempt
+		return w.call(handlerCtx, value)
+	case err != nil && ctx.Err() != nil:
+		// waiting for the next attempt was interrupted
+		return fmt.Errorf("%w: %w", errRetryInterrupted, last)
+	}
+
+	return err
+}
+
+// call runs the handler and converts panics to errors
+func (w *Worker) call(ctx context.Context, value string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errPkg.NewInternalMsg(fmt.Errorf("%v", r), "handler panicked")
+		}
+	}()
+
+	return w.handler(ctx, value)
+}
+
+func (w *Worker) logFailure(ctx context.Context, err error) {
+	if logger := ctxPkg.GetLogger(ctx); logger != nil {
+		logger.Errorf(ctx, "failed to handle element: %v", err)
+	}
+}
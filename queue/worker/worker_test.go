@@ -0,0 +1,268 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tmeisel/glib/exec/backoff"
+	"github.com/tmeisel/glib/queue"
+)
+
+type testQueue struct {
+	mu     sync.Mutex
+	values []string
+}
+
+func newTestQueue(values ...string) *testQueue {
+	return &testQueue{values: values}
+}
+
+func (q *testQueue) Push(value string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.values = append(q.values, value)
+	return nil
+}
+
+func (q *testQueue) Pop() (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.values) == 0 {
+		return "", queue.ErrEmpty
+	}
+
+	value := q.values[0]
+	q.values = q.values[1:]
+
+	return value, nil
+}
+
+func (q *testQueue) Empty() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.values = nil
+	return nil
+}
+
+func (q *testQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.values)
+}
+
+type testReliableQueue struct {
+	*testQueue
+
+	acked  atomic.Int32
+	nacked atomic.Int32
+}
+
+func (q *testReliableQueue) Receive() (queue.Message, error) {
+	value, err := q.Pop()
+	if err != nil {
+		return nil, err
+	}
+
+	return testMessage{q: q, value: value}, nil
+}
+
+func (q *testReliableQueue) Requeue() (int, error) {
+	return 0, nil
+}
+
+type testMessage struct {
+	q     *testReliableQueue
+	value string
+}
+
+func (m testMessage) Value() string {
+	return m.value
+}
+
+func (m testMessage) Ack() error {
+	m.q.acked.Add(1)
+	return nil
+}
+
+func (m testMessage) Nack() error {
+	m.q.nacked.Add(1)
+	return nil
+}
+
+// runUntil runs w until condition is true or the test times out
+func runUntil(t *testing.T, w *Worker, condition func() bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, condition, time.Second*5, time.Millisecond*10)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("worker did not stop")
+	}
+}
+
+func TestWorker_Run(t *testing.T) {
+	q := newTestQueue("a", "b", "c", "d", "e")
+
+	var handled atomic.Int32
+	w := New(q, func(_ context.Context, _ string) error {
+		handled.Add(1)
+		return nil
+	}, WithConcurrency(3), WithIdleTimeout(time.Millisecond))
+
+	runUntil(t, w, func() bool {
+		return handled.Load() == 5
+	})
+
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	q := newTestQueue("a")
+
+	var attempts atomic.Int32
+	w := New(q, func(_ context.Context, _ string) error {
+		if attempts.Add(1) < 3 {
+			return backoff.RetryableError(errors.New("temporary"))
+		}
+
+		return nil
+	}, WithBackoff(func() *backoff.Backoff {
+		return backoff.NewConstant(time.Millisecond)
+	}))
+
+	runUntil(t, w, func() bool {
+		return attempts.Load() == 3
+	})
+}
+
+func TestWorker_BackoffPerElement(t *testing.T) {
+	q := newTestQueue("a", "b")
+
+	var attempts atomic.Int32
+	w := New(q, func(_ context.Context, _ string) error {
+		attempts.Add(1)
+		return backoff.RetryableError(errors.New("temporary"))
+	}, WithBackoff(func() *backoff.Backoff {
+		b := backoff.NewConstant(time.Millisecond)
+		b.With(backoff.WithMaxRetries(2))
+
+		return b
+	}))
+
+	runUntil(t, w, func() bool {
+		return q.Len() == 0 && attempts.Load() == 6
+	})
+
+	// both elements are attempted 1 + 2 times
+	assert.Equal(t, int32(6), attempts.Load())
+}
+
+func TestWorker_BackoffShutdown(t *testing.T) {
+	q := newTestQueue("a")
+
+	var attempts atomic.Int32
+	w := New(q, func(_ context.Context, _ string) error {
+		attempts.Add(1)
+		return backoff.RetryableError(errors.New("temporary"))
+	}, WithBackoff(func() *backoff.Backoff {
+		return backoff.NewConstant(time.Hour)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return attempts.Load() == 1
+	}, time.Second*5, time.Millisecond*10)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("worker did not stop while waiting for the next attempt")
+	}
+
+	assert.Equal(t, int32(1), attempts.Load())
+
+	// the element is returned to the queue
+	assert.Equal(t, 1, q.Len())
+}
+
+func TestWorker_Reliable(t *testing.T) {
+	q := &testReliableQueue{testQueue: newTestQueue("ok", "fail", "panic")}
+
+	w := New(q, func(_ context.Context, value string) error {
+		switch value {
+		case "fail":
+			return errors.New("failed")
+		case "panic":
+			panic("oops")
+		}
+
+		return nil
+	})
+
+	runUntil(t, w, func() bool {
+		return q.acked.Load() == 1 && q.nacked.Load() == 2
+	})
+}
+
+func TestWorker_Drain(t *testing.T) {
+	q := newTestQueue("slow")
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+
+	w := New(q, func(ctx context.Context, _ string) error {
+		close(started)
+		time.Sleep(time.Millisecond * 200)
+
+		// the handler context is not cancelled on shutdown
+		if ctx.Err() == nil {
+			finished.Store(true)
+		}
+
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	<-started
+	cancel()
+	<-done
+
+	assert.True(t, finished.Load())
+}